@@ -0,0 +1,231 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"click-guardian/internal/filter"
+	"click-guardian/internal/trace"
+	"click-guardian/internal/version"
+)
+
+func main() {
+	var (
+		path      string
+		showAll   bool
+		overrides []func(*trace.Settings) error
+	)
+
+	args := os.Args[1:]
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		value := func() string {
+			if i+1 >= len(args) {
+				fail("%s needs a value", arg)
+			}
+			i++
+			return args[i]
+		}
+
+		switch arg {
+		case "--delay":
+			ms := parseMs(arg, value())
+			overrides = append(overrides, func(s *trace.Settings) error {
+				s.DelayMs = ms
+				return nil
+			})
+		case "--button-delay":
+			name, ms, ok := strings.Cut(value(), "=")
+			if !ok {
+				fail("--button-delay expects BUTTON=MS")
+			}
+			delay := parseMs(arg, ms)
+			overrides = append(overrides, func(s *trace.Settings) error {
+				if _, ok := filter.ParseButton(name); !ok {
+					return fmt.Errorf("unknown button %q", name)
+				}
+				if s.ButtonDelayMs == nil {
+					s.ButtonDelayMs = make(map[string]int)
+				}
+				s.ButtonDelayMs[strings.ToLower(name)] = delay
+				return nil
+			})
+		case "--disable":
+			name := value()
+			overrides = append(overrides, func(s *trace.Settings) error {
+				if _, ok := filter.ParseButton(name); !ok {
+					return fmt.Errorf("unknown button %q", name)
+				}
+				s.Disabled = append(s.Disabled, strings.ToLower(name))
+				return nil
+			})
+		case "--wheel-window":
+			ms := parseMs(arg, value())
+			overrides = append(overrides, func(s *trace.Settings) error {
+				s.WheelFilter = true
+				s.WheelWindowMs = ms
+				return nil
+			})
+		case "--no-wheel":
+			overrides = append(overrides, func(s *trace.Settings) error {
+				s.WheelFilter = false
+				return nil
+			})
+		case "--strategy":
+			name := value()
+			overrides = append(overrides, func(s *trace.Settings) error {
+				strategy, ok := filter.ParseStrategy(name)
+				if !ok {
+					return fmt.Errorf("unknown strategy %q", name)
+				}
+				s.Strategy = strategy.String()
+				return nil
+			})
+		case "--double-click-window":
+			ms := parseMs(arg, value())
+			overrides = append(overrides, func(s *trace.Settings) error {
+				if ms == 0 {
+					return fmt.Errorf("--double-click-window must be above 0ms")
+				}
+				s.DoubleClickWindowMs = ms
+				return nil
+			})
+		case "--all":
+			showAll = true
+		case "--version", "-v":
+			fmt.Println(version.GetFullVersionString())
+			return
+		case "--help", "-h":
+			showHelp()
+			return
+		default:
+			if strings.HasPrefix(arg, "-") || path != "" {
+				fail("unexpected argument %q", arg)
+			}
+			path = arg
+		}
+	}
+
+	if path == "" {
+		showHelp()
+		os.Exit(2)
+	}
+
+	t, err := trace.Load(path)
+	if err != nil {
+		fail("%v", err)
+	}
+
+	settings := t.Header.Settings
+	for _, override := range overrides {
+		if err := override(&settings); err != nil {
+			fail("%v", err)
+		}
+	}
+
+	recorded, replayed := t.Header.Settings.Config(), settings.Config()
+	results, summary, err := t.Replay(replayed)
+	if err != nil {
+		fail("%v", err)
+	}
+
+	fmt.Printf("Trace recorded %s with %dms delay (%s), replayed with %dms delay (%s)\n",
+		t.Header.Started.Format("2006-01-02 15:04:05"), t.Header.Settings.DelayMs, recorded.Strategy,
+		settings.DelayMs, replayed.Strategy)
+	for _, r := range results {
+		if r.Event.Kind == filter.KindMove || (!showAll && !r.Changed()) {
+			continue
+		}
+		fmt.Println(describe(r))
+	}
+
+	fmt.Println()
+	fmt.Printf("Events:           %d\n", summary.Events)
+	fmt.Printf("Blocked recorded: %d\n", summary.RecordedBlocked)
+	fmt.Printf("Blocked replayed: %d\n", summary.ReplayBlocked)
+	fmt.Printf("Newly blocked:    %d\n", summary.NewlyBlocked)
+	fmt.Printf("Newly allowed:    %d\n", summary.NewlyAllowed)
+}
+
+// describe formats one replayed event
+func describe(r trace.Result) string {
+	offset := time.Duration(r.Entry.Offset).Round(time.Millisecond)
+
+	what := "wheel " + strconv.Itoa(r.Entry.Delta)
+	switch r.Event.Kind {
+	case filter.KindDown:
+		what = r.Event.Button.String() + " DOWN"
+	case filter.KindUp:
+		what = r.Event.Button.String() + " UP"
+	}
+	if r.Entry.Device != "" {
+		what += " [" + r.Entry.Device + "]"
+	}
+	if r.Entry.Bypassed {
+		what += " bypassed"
+	}
+
+	outcome := func(allowed bool, reason string) string {
+		if allowed {
+			return "allowed"
+		}
+		return "blocked (" + reason + ")"
+	}
+
+	replayed := outcome(r.Decision.Allow, r.Decision.Reason.String())
+	if !r.Decision.Allow {
+		replayed = fmt.Sprintf("blocked (%s, %dms < %dms)", r.Decision.Reason,
+			r.Decision.Interval.Milliseconds(), r.Decision.Delay.Milliseconds())
+	}
+
+	marker := " "
+	if r.Changed() {
+		marker = "*"
+	}
+	return fmt.Sprintf("%s %10s  %-24s recorded %s, replay %s",
+		marker, offset, what, outcome(r.Entry.Allowed, r.Entry.Reason), replayed)
+}
+
+func parseMs(flag, value string) int {
+	ms, err := strconv.Atoi(value)
+	if err != nil || ms < 0 {
+		fail("%s expects milliseconds, got %q", flag, value)
+	}
+	return ms
+}
+
+func fail(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "click-guardian-replay: "+format+"\n", args...)
+	os.Exit(1)
+}
+
+// showHelp displays command-line usage information
+func showHelp() {
+	fmt.Println("Replays a Click Guardian trace through the filter with different settings.")
+	fmt.Println()
+
+	fmt.Println("Usage:")
+	fmt.Printf("  %s [options] TRACE\n\n", os.Args[0])
+
+	fmt.Println("Options:")
+	fmt.Println("  --delay MS              Use a different global delay")
+	fmt.Println("  --button-delay BTN=MS   Override the delay of one button (left, right, middle, x1, x2)")
+	fmt.Println("  --disable BTN           Stop filtering a button")
+	fmt.Println("  --wheel-window MS       Enable the wheel filter with this window")
+	fmt.Println("  --no-wheel              Disable the wheel filter")
+	fmt.Println("  --strategy NAME         Use another filter strategy (strict, double-click)")
+	fmt.Println("  --double-click-window MS")
+	fmt.Println("                          Longest release-to-press gap of a double-click")
+	fmt.Println("  --all                   List every event, not only changed decisions")
+	fmt.Println("  --version, -v           Show version information")
+	fmt.Println("  --help, -h              Show this help message")
+	fmt.Println()
+
+	fmt.Println("Examples:")
+	fmt.Printf("  %s trace.jsonl --delay 60\n", os.Args[0])
+	fmt.Printf("  %s trace.jsonl --button-delay right=80 --all\n", os.Args[0])
+	fmt.Printf("  %s trace.jsonl --strategy double-click --double-click-window 400\n", os.Args[0])
+}
@@ -1,10 +1,13 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
+	"click-guardian/internal/control"
 	"click-guardian/internal/gui"
+	"click-guardian/internal/headless"
 	"click-guardian/internal/version"
 )
 
@@ -12,13 +15,25 @@ func main() {
 	// Check for command line arguments
 	startMinimized := false
 	autoProtect := false
+	runHeadless := false
+	logFile := ""
 
-	for _, arg := range os.Args[1:] {
-		switch arg {
+	args := os.Args[1:]
+	for i := 0; i < len(args); i++ {
+		switch arg := args[i]; arg {
 		case "--minimized":
 			startMinimized = true
 		case "--auto-protect":
 			autoProtect = true
+		case "--headless":
+			runHeadless = true
+		case "--log-file":
+			if i+1 >= len(args) {
+				fmt.Fprintln(os.Stderr, "--log-file needs a path")
+				os.Exit(2)
+			}
+			i++
+			logFile = args[i]
 		case "--version", "-v":
 			fmt.Println(version.GetFullVersionString())
 			return
@@ -28,7 +43,29 @@ func main() {
 		}
 	}
 
+	if runHeadless {
+		if err := headless.Run(headless.Options{LogFile: logFile}); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Only one instance may filter mouse input; a second launch hands its
+	// flags to the running one and exits
+	server, err := control.Listen()
+	if errors.Is(err, control.ErrAlreadyRunning) {
+		forwardToRunningInstance(startMinimized, autoProtect)
+		return
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+
 	app := gui.NewApplication()
+	if server != nil {
+		app.SetControlServer(server)
+	}
 	if startMinimized {
 		app.RunMinimized()
 	} else {
@@ -40,6 +77,32 @@ func main() {
 	}
 }
 
+// forwardToRunningInstance asks the running instance to show its window
+// and, when requested, to start protection
+func forwardToRunningInstance(startMinimized, autoProtect bool) {
+	client, err := control.Dial()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Click Guardian is already running but could not be reached: %v\n", err)
+		os.Exit(1)
+	}
+	defer client.Close()
+
+	status, err := client.Activate(control.ActivateArgs{
+		ShowWindow:      !startMinimized,
+		StartProtection: autoProtect || startMinimized,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	state := "inactive"
+	if status.Running {
+		state = "active"
+	}
+	fmt.Printf("Click Guardian is already running (protection %s)\n", state)
+}
+
 // showHelp displays command-line usage information
 func showHelp() {
 	info := version.GetAppInfo()
@@ -52,6 +115,8 @@ func showHelp() {
 	fmt.Println("Options:")
 	fmt.Println("  --minimized      Start minimized to system tray")
 	fmt.Println("  --auto-protect   Start with protection automatically enabled")
+	fmt.Println("  --headless       Run without a window, logging to stdout until interrupted")
+	fmt.Println("  --log-file PATH  Also append headless log output to PATH")
 	fmt.Println("  --version, -v    Show version information")
 	fmt.Println("  --help, -h       Show this help message")
 	fmt.Println()
@@ -60,6 +125,7 @@ func showHelp() {
 	fmt.Printf("  %s                    # Start normally\n", os.Args[0])
 	fmt.Printf("  %s --minimized        # Start minimized to tray\n", os.Args[0])
 	fmt.Printf("  %s --auto-protect     # Start with protection enabled\n", os.Args[0])
+	fmt.Printf("  %s --headless         # Run as a background service\n", os.Args[0])
 	fmt.Println()
 
 	fmt.Printf("%s\n", info.Copyright)
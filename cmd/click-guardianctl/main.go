@@ -0,0 +1,289 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"click-guardian/internal/control"
+	"click-guardian/internal/filter"
+	"click-guardian/internal/version"
+)
+
+// Exit codes
+const (
+	exitError      = 1
+	exitUsage      = 2
+	exitNotRunning = 3
+)
+
+func main() {
+	var (
+		command    string
+		args       []string
+		jsonOutput bool
+		follow     bool
+	)
+	for _, arg := range os.Args[1:] {
+		switch arg {
+		case "--json":
+			jsonOutput = true
+		case "--follow", "-f":
+			follow = true
+		case "--version", "-v":
+			fmt.Println(version.GetFullVersionString())
+			return
+		case "--help", "-h":
+			showHelp()
+			return
+		default:
+			if strings.HasPrefix(arg, "-") {
+				usageError("unknown option %q", arg)
+			}
+			if command == "" {
+				command = arg
+			} else {
+				args = append(args, arg)
+			}
+		}
+	}
+
+	if command == "" || command == "help" {
+		showHelp()
+		if command == "" {
+			os.Exit(exitUsage)
+		}
+		return
+	}
+
+	switch command {
+	case "status", "start", "stop", "snooze", "get-delay", "set-delay", "stats", "health", "events":
+	default:
+		usageError("unknown command %q", command)
+	}
+
+	client, err := control.Dial()
+	if err != nil {
+		fail(err)
+	}
+	defer client.Close()
+
+	switch command {
+	case "status":
+		status, err := client.Status()
+		check(err)
+		printStatus(status, jsonOutput)
+	case "start":
+		status, err := client.Start()
+		check(err)
+		printStatus(status, jsonOutput)
+	case "stop":
+		status, err := client.Stop()
+		check(err)
+		printStatus(status, jsonOutput)
+	case "snooze":
+		if len(args) != 1 {
+			usageError("snooze expects a duration in minutes")
+		}
+		minutes, err := strconv.Atoi(args[0])
+		if err != nil {
+			usageError("invalid duration %q", args[0])
+		}
+		status, err := client.Snooze(minutes)
+		check(err)
+		printStatus(status, jsonOutput)
+	case "get-delay":
+		delay, err := client.GetDelay()
+		check(err)
+		printDelay(delay, jsonOutput)
+	case "set-delay":
+		if len(args) != 1 {
+			usageError("set-delay expects a delay in milliseconds")
+		}
+		ms, err := strconv.Atoi(args[0])
+		if err != nil {
+			usageError("invalid delay %q", args[0])
+		}
+		delay, err := client.SetDelay(ms)
+		check(err)
+		printDelay(delay, jsonOutput)
+	case "stats":
+		stats, err := client.Stats()
+		check(err)
+		printStats(stats, jsonOutput)
+	case "health":
+		reply, err := client.Health()
+		check(err)
+		printHealth(reply, jsonOutput)
+	case "events":
+		tailEvents(client, follow, jsonOutput)
+	}
+}
+
+func printStatus(status control.Status, jsonOutput bool) {
+	if jsonOutput {
+		printJSON(status)
+		return
+	}
+	state := "inactive"
+	if status.Running {
+		state = "active"
+	} else if !status.SnoozedUntil.IsZero() {
+		state = "snoozed until " + status.SnoozedUntil.Local().Format("15:04:05")
+	}
+	fmt.Printf("Protection:     %s\n", state)
+	fmt.Printf("Delay:          %d ms\n", status.DelayMs)
+	fmt.Printf("Blocked clicks: %d\n", status.BlockedClicks)
+	fmt.Printf("Instance:       v%s (%s)\n", status.Version, status.Mode)
+}
+
+func printDelay(delay int, jsonOutput bool) {
+	if jsonOutput {
+		printJSON(control.DelayReply{DelayMs: delay})
+		return
+	}
+	fmt.Printf("%d ms\n", delay)
+}
+
+func printStats(stats control.Stats, jsonOutput bool) {
+	if jsonOutput {
+		printJSON(stats)
+		return
+	}
+
+	fmt.Printf("%-8s %8s %8s\n", "Button", "Allowed", "Blocked")
+	for _, button := range filter.Buttons() {
+		bs := stats.Buttons[strings.ToLower(button.String())]
+		blocked := 0
+		for reason, n := range bs.Blocked {
+			if reason != filter.ReasonSpuriousUp.String() {
+				blocked += n
+			}
+		}
+		fmt.Printf("%-8s %8d %8d\n", button, bs.Allowed, blocked)
+
+		reasons := make([]string, 0, len(bs.Blocked))
+		for reason := range bs.Blocked {
+			reasons = append(reasons, reason)
+		}
+		sort.Strings(reasons)
+		for _, reason := range reasons {
+			fmt.Printf("  %-24s %d\n", reason, bs.Blocked[reason])
+		}
+	}
+	fmt.Printf("%-8s %8d %8d\n", "Total", stats.TotalAllowed, stats.TotalBlocked)
+	fmt.Printf("Wheel glitches blocked: %d of %d ticks\n", stats.WheelBlocked, stats.WheelAllowed+stats.WheelBlocked)
+}
+
+func printHealth(reply control.HealthReply, jsonOutput bool) {
+	if jsonOutput {
+		printJSON(reply)
+		return
+	}
+
+	fmt.Printf("Health over the last %d days\n", reply.WindowDays)
+	fmt.Printf("%-8s %6s %-8s %8s %8s %8s\n", "Button", "Score", "Level", "Clicks", "Bounces", "Trend")
+	var advice []string
+	for _, h := range reply.Buttons {
+		name := h.Button
+		if button, ok := filter.ParseButton(h.Button); ok {
+			name = button.String()
+		}
+		score := "-"
+		if h.Score >= 0 {
+			score = strconv.Itoa(h.Score)
+		}
+		fmt.Printf("%-8s %6s %-8s %8d %7.1f%% %+8.1f\n", name, score, h.Level, h.Clicks, 100*h.BounceRate, 100*h.Trend)
+		if h.Level == "worn" || h.Level == "failing" {
+			advice = append(advice, fmt.Sprintf("%s: %s", name, h.Recommendation))
+		}
+	}
+	for _, line := range advice {
+		fmt.Println(line)
+	}
+}
+
+// tailEvents prints the recent events and, when following, waits for new ones
+func tailEvents(client *control.Client, follow, jsonOutput bool) {
+	var after uint64
+	for {
+		timeout := 0
+		if follow {
+			timeout = 10000
+		}
+		reply, err := client.Events(after, timeout)
+		check(err)
+
+		for _, ev := range reply.Events {
+			if jsonOutput {
+				data, _ := json.Marshal(ev)
+				fmt.Println(string(data))
+			} else if ev.Message != "" {
+				fmt.Printf("[%s] %s\n", ev.Time.Local().Format("15:04:05"), ev.Message)
+			}
+		}
+		after = reply.Next
+
+		if !follow {
+			return
+		}
+	}
+}
+
+func printJSON(v interface{}) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	check(err)
+	fmt.Println(string(data))
+}
+
+func check(err error) {
+	if err != nil {
+		fail(err)
+	}
+}
+
+func fail(err error) {
+	fmt.Fprintf(os.Stderr, "click-guardianctl: %v\n", err)
+	if control.IsNotRunning(err) {
+		os.Exit(exitNotRunning)
+	}
+	os.Exit(exitError)
+}
+
+func usageError(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "click-guardianctl: "+format+"\n", args...)
+	os.Exit(exitUsage)
+}
+
+// showHelp displays command-line usage information
+func showHelp() {
+	fmt.Println("Controls a running Click Guardian instance.")
+	fmt.Println()
+
+	fmt.Println("Usage:")
+	fmt.Printf("  %s [options] COMMAND [ARGS]\n\n", os.Args[0])
+
+	fmt.Println("Commands:")
+	fmt.Println("  status           Show whether protection is active")
+	fmt.Println("  start            Start protection")
+	fmt.Println("  stop             Stop protection")
+	fmt.Println("  snooze MINUTES   Stop protection and start it again after MINUTES")
+	fmt.Println("  get-delay        Show the global delay")
+	fmt.Println("  set-delay MS     Change the global delay (5-500 ms)")
+	fmt.Println("  stats            Show allowed and blocked clicks per button")
+	fmt.Println("  health           Show the health score of each button (0-100)")
+	fmt.Println("  events           Show recent events")
+	fmt.Println()
+
+	fmt.Println("Options:")
+	fmt.Println("  --json           Print machine-readable JSON (one object per line for events)")
+	fmt.Println("  --follow, -f     Keep printing new events")
+	fmt.Println("  --version, -v    Show version information")
+	fmt.Println("  --help, -h       Show this help message")
+	fmt.Println()
+
+	fmt.Println("Exit status is 3 when no instance is running.")
+}
@@ -5,6 +5,7 @@ go 1.24.1
 require (
 	fyne.io/fyne/v2 v2.6.1
 	fyne.io/systray v1.11.0
+	golang.org/x/sys v0.33.0
 )
 
 require (
@@ -36,7 +37,6 @@ require (
 	github.com/yuin/goldmark v1.7.8 // indirect
 	golang.org/x/image v0.24.0 // indirect
 	golang.org/x/net v0.35.0 // indirect
-	golang.org/x/sys v0.33.0 // indirect
 	golang.org/x/text v0.22.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -0,0 +1,286 @@
+package calibration
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"click-guardian/internal/config"
+	"click-guardian/internal/filter"
+)
+
+const (
+	maxChatterGap   = 100 * time.Millisecond // Switch chatter is never slower than this
+	minHumanGap     = 40 * time.Millisecond  // Nobody presses again this soon on purpose
+	maxDoubleClick  = 600 * time.Millisecond // Slower second presses start a new click
+	minClusterRatio = 3.0                    // Double-click gaps must be this many times slower than chatter
+	noChatterDelay  = 30 * time.Millisecond  // Recommended when the switch did not chatter, and the least otherwise
+	delayStep       = 5 * time.Millisecond   // Recommendations are rounded up to this
+)
+
+// Step is one guided exercise of the calibration
+type Step int
+
+const (
+	StepSingleClicks Step = iota // Separate clicks with a pause in between
+	StepDoubleClicks             // Deliberate double-clicks
+	StepDrags                    // Press, move and release
+)
+
+// Steps returns the exercises in the order they are performed
+func Steps() []Step {
+	return []Step{StepSingleClicks, StepDoubleClicks, StepDrags}
+}
+
+// Title returns a short name of the step
+func (s Step) Title() string {
+	switch s {
+	case StepSingleClicks:
+		return "Single clicks"
+	case StepDoubleClicks:
+		return "Double-clicks"
+	case StepDrags:
+		return "Drags"
+	default:
+		return "Unknown"
+	}
+}
+
+// Instructions tells the user what to do during the step
+func (s Step) Instructions() string {
+	switch s {
+	case StepSingleClicks:
+		return fmt.Sprintf("Click the pad %d times, pausing about half a second between clicks.", s.Target())
+	case StepDoubleClicks:
+		return fmt.Sprintf("Double-click the pad %d times, as you would to open a file.", s.Target())
+	case StepDrags:
+		return fmt.Sprintf("Press on the pad, move the mouse while holding and release, %d times.", s.Target())
+	default:
+		return ""
+	}
+}
+
+// Target is how many clicks, double-clicks or drags the step asks for
+func (s Step) Target() int {
+	switch s {
+	case StepSingleClicks:
+		return 10
+	case StepDoubleClicks:
+		return 6
+	case StepDrags:
+		return 5
+	default:
+		return 0
+	}
+}
+
+// press is a raw press of a button and the gap since its previous release
+type press struct {
+	step Step
+	gap  time.Duration // Zero for the first press of a step
+}
+
+// Session collects the raw presses of every button while the user performs
+// the steps. Observe matches filter.Observer and may be called from the hook.
+type Session struct {
+	mu      sync.Mutex
+	step    Step
+	presses map[filter.Button][]press
+	lastUp  map[filter.Button]time.Time
+	actions map[Step]int
+}
+
+// NewSession starts a calibration at the first step
+func NewSession() *Session {
+	return &Session{
+		presses: make(map[filter.Button][]press),
+		lastUp:  make(map[filter.Button]time.Time),
+		actions: make(map[Step]int),
+	}
+}
+
+// SetStep moves to another step; gaps are not measured across steps
+func (s *Session) SetStep(step Step) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.step = step
+	s.lastUp = make(map[filter.Button]time.Time)
+}
+
+// Observe records a raw button event, whatever the filter decided
+func (s *Session) Observe(ev filter.Event, _ filter.Decision) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch ev.Kind {
+	case filter.KindDown:
+		var gap time.Duration
+		if last, ok := s.lastUp[ev.Button]; ok {
+			gap = ev.Time.Sub(last)
+		}
+		s.presses[ev.Button] = append(s.presses[ev.Button], press{step: s.step, gap: gap})
+		s.countAction(gap)
+	case filter.KindUp:
+		s.lastUp[ev.Button] = ev.Time
+	}
+}
+
+// countAction advances the progress of the step; chatter is not an action
+// and the second press of a double-click completes the first one
+func (s *Session) countAction(gap time.Duration) {
+	if s.step == StepDoubleClicks {
+		if gap >= minHumanGap && gap < maxDoubleClick {
+			s.actions[s.step]++
+		}
+		return
+	}
+	if gap == 0 || gap >= maxChatterGap {
+		s.actions[s.step]++
+	}
+}
+
+// Progress returns how many clicks, double-clicks or drags of a step were done
+func (s *Session) Progress(step Step) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.actions[step]
+}
+
+// Results analyzes the recorded presses of every button that was used
+func (s *Session) Results() []Result {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var results []Result
+	for _, button := range filter.Buttons() {
+		if presses := s.presses[button]; len(presses) > 0 {
+			results = append(results, analyze(button, presses))
+		}
+	}
+	return results
+}
+
+// Result is the calibration outcome for one button
+type Result struct {
+	Button         filter.Button
+	Presses        int
+	Chatter        int           // Presses that were switch chatter
+	MaxChatter     time.Duration // Slowest chatter gap, zero without chatter
+	MinDoubleClick time.Duration // Fastest intentional double-click gap, zero when none was made
+	Delay          time.Duration // Recommended delay
+
+	// Conflict is set when the switch chatters as slowly as the user
+	// double-clicks; the delay then blocks the chatter and fast double-clicks
+	Conflict bool
+}
+
+// DelayMs returns the recommended delay in milliseconds
+func (r Result) DelayMs() int {
+	return int(r.Delay / time.Millisecond)
+}
+
+// Summary describes the measurements and the recommendation
+func (r Result) Summary() string {
+	text := fmt.Sprintf("%s: %d presses", r.Button, r.Presses)
+	if r.Chatter > 0 {
+		text += fmt.Sprintf(", %d chatter (up to %d ms)", r.Chatter, r.MaxChatter.Milliseconds())
+	} else {
+		text += ", no chatter"
+	}
+	if r.MinDoubleClick > 0 {
+		text += fmt.Sprintf(", fastest double-click %d ms", r.MinDoubleClick.Milliseconds())
+	}
+	text += fmt.Sprintf(". Recommended delay: %d ms", r.DelayMs())
+	if r.Conflict {
+		text += " (double-clicks faster than this will be blocked)"
+	}
+	return text
+}
+
+// Analyze recommends a delay from raw button events recorded per step.
+// It is the offline counterpart of Session for recorded timing data.
+func Analyze(events map[Step][]filter.Event) []Result {
+	session := NewSession()
+	for _, step := range Steps() {
+		session.SetStep(step)
+		for _, ev := range events[step] {
+			session.Observe(ev, filter.Decision{})
+		}
+	}
+	return session.Results()
+}
+
+// analyze splits the release-to-press gaps of a button into chatter and
+// intentional presses. The filter blocks a press when this gap is below the
+// delay, so the delay has to sit between the two groups.
+func analyze(button filter.Button, presses []press) Result {
+	r := Result{Button: button, Presses: len(presses)}
+
+	// Outside the double-click step every quick press is chatter
+	var doubleGaps []time.Duration
+	for _, p := range presses {
+		switch {
+		case p.gap <= 0:
+		case p.step == StepDoubleClicks && p.gap < maxDoubleClick:
+			doubleGaps = append(doubleGaps, p.gap)
+		case p.gap < maxChatterGap:
+			r.Chatter++
+			r.MaxChatter = max(r.MaxChatter, p.gap)
+		}
+	}
+
+	// Double-clicks mix both groups, split them where the gaps jump
+	chatter, intentional := split(doubleGaps)
+	for _, gap := range chatter {
+		r.Chatter++
+		r.MaxChatter = max(r.MaxChatter, gap)
+	}
+	if len(intentional) > 0 {
+		r.MinDoubleClick = intentional[0]
+	}
+
+	r.Delay = recommend(r.MaxChatter, r.MinDoubleClick)
+	r.Conflict = r.MinDoubleClick > 0 && r.Delay > r.MinDoubleClick
+	return r
+}
+
+// split sorts gaps and divides them at the largest jump of at least
+// minClusterRatio. Gaps below minHumanGap are always chatter and gaps above
+// the chatter limit are always intentional.
+func split(gaps []time.Duration) (chatter, intentional []time.Duration) {
+	sort.Slice(gaps, func(i, j int) bool { return gaps[i] < gaps[j] })
+
+	floor := sort.Search(len(gaps), func(i int) bool { return gaps[i] >= minHumanGap })
+	cut, best := 0, minClusterRatio
+	for i := 1; i < len(gaps); i++ {
+		if gaps[i-1] >= maxChatterGap {
+			break
+		}
+		if ratio := float64(gaps[i]) / float64(gaps[i-1]); ratio >= best {
+			cut, best = i, ratio
+		}
+	}
+	cut = max(cut, floor)
+	return gaps[:cut], gaps[cut:]
+}
+
+// recommend places the delay above the chatter with a safety margin, but
+// below the fastest double-click when both can be satisfied
+func recommend(maxChatter, minDoubleClick time.Duration) time.Duration {
+	delay := noChatterDelay
+	if maxChatter > 0 {
+		// Chatter seen during a short calibration can get slower later on
+		delay = max(maxChatter*3/2, maxChatter+10*time.Millisecond, noChatterDelay)
+	}
+
+	if minDoubleClick > 0 {
+		limit := minDoubleClick * 4 / 5
+		if delay > limit {
+			delay = max(min(delay, limit), maxChatter+delayStep)
+		}
+	}
+
+	delay = (delay + delayStep - 1) / delayStep * delayStep
+	return min(max(delay, config.MinDelayMs*time.Millisecond), config.MaxDelayMs*time.Millisecond)
+}
@@ -0,0 +1,183 @@
+package calibration
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"click-guardian/internal/filter"
+)
+
+// clicks returns left button presses held for 60ms. The first press comes
+// at zero, every further one the given milliseconds after the previous
+// release.
+func clicks(gaps ...int) []filter.Event {
+	start := time.Date(2024, 3, 30, 12, 0, 0, 0, time.UTC)
+	hold := 60 * time.Millisecond
+
+	var events []filter.Event
+	at := start
+	for i := 0; i <= len(gaps); i++ {
+		if i > 0 {
+			at = at.Add(time.Duration(gaps[i-1]) * time.Millisecond)
+		}
+		events = append(events,
+			filter.Event{Kind: filter.KindDown, Button: filter.ButtonLeft, Time: at},
+			filter.Event{Kind: filter.KindUp, Button: filter.ButtonLeft, Time: at.Add(hold)})
+		at = at.Add(hold)
+	}
+	return events
+}
+
+func TestAnalyze(t *testing.T) {
+	ms := time.Millisecond
+
+	tests := []struct {
+		name   string
+		events map[Step][]filter.Event
+		want   Result
+	}{
+		{
+			name:   "clean switch",
+			events: map[Step][]filter.Event{StepSingleClicks: clicks(500, 500, 500, 500)},
+			want:   Result{Presses: 5, Delay: 30 * ms},
+		},
+		{
+			// 40ms chatter gets a 50% margin
+			name:   "bounces between single clicks",
+			events: map[Step][]filter.Event{StepSingleClicks: clicks(500, 15, 500, 40, 500)},
+			want:   Result{Presses: 6, Chatter: 2, MaxChatter: 40 * ms, Delay: 60 * ms},
+		},
+		{
+			name: "double-clicks split from their bounces",
+			events: map[Step][]filter.Event{
+				StepDoubleClicks: clicks(150, 8, 1000, 160, 10, 1000, 170),
+			},
+			want: Result{Presses: 8, Chatter: 2, MaxChatter: 10 * ms, MinDoubleClick: 150 * ms, Delay: 30 * ms},
+		},
+		{
+			name: "only intentional double-clicks",
+			events: map[Step][]filter.Event{
+				StepDoubleClicks: clicks(150, 1000, 160, 1000, 170),
+			},
+			want: Result{Presses: 6, MinDoubleClick: 150 * ms, Delay: 30 * ms},
+		},
+		{
+			// Nobody double-clicks within 40ms, so one fast cluster is chatter
+			name: "only bounces during double-clicks",
+			events: map[Step][]filter.Event{
+				StepDoubleClicks: clicks(20, 1000, 25, 1000, 30),
+			},
+			want: Result{Presses: 6, Chatter: 3, MaxChatter: 30 * ms, Delay: 45 * ms},
+		},
+		{
+			// The delay stays below the double-clicks when the chatter allows
+			name: "double-clicks cap the margin",
+			events: map[Step][]filter.Event{
+				StepSingleClicks: clicks(500, 60, 500),
+				StepDoubleClicks: clicks(100, 1000, 110),
+			},
+			want: Result{Presses: 8, Chatter: 1, MaxChatter: 60 * ms, MinDoubleClick: 100 * ms, Delay: 80 * ms},
+		},
+		{
+			name: "chatter as slow as double-clicks",
+			events: map[Step][]filter.Event{
+				StepSingleClicks: clicks(500, 90, 500),
+				StepDoubleClicks: clicks(92, 1000, 94),
+			},
+			want: Result{Presses: 8, Chatter: 1, MaxChatter: 90 * ms, MinDoubleClick: 92 * ms, Delay: 95 * ms, Conflict: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := Analyze(tt.events)
+			if len(results) != 1 {
+				t.Fatalf("got %d results, want one for the left button", len(results))
+			}
+			tt.want.Button = filter.ButtonLeft
+			if results[0] != tt.want {
+				t.Errorf("got  %+v\nwant %+v", results[0], tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyzeWithoutSamples(t *testing.T) {
+	if results := Analyze(nil); len(results) != 0 {
+		t.Errorf("got %v without any events, want no results", results)
+	}
+
+	// Releases and moves alone are no presses
+	events := map[Step][]filter.Event{StepDrags: {
+		{Kind: filter.KindMove, Time: time.Now()},
+		{Kind: filter.KindUp, Button: filter.ButtonRight, Time: time.Now()},
+	}}
+	if results := Analyze(events); len(results) != 0 {
+		t.Errorf("got %v without presses, want no results", results)
+	}
+}
+
+func TestSplit(t *testing.T) {
+	ms := func(values ...int) []time.Duration {
+		var gaps []time.Duration
+		for _, v := range values {
+			gaps = append(gaps, time.Duration(v)*time.Millisecond)
+		}
+		return gaps
+	}
+
+	tests := []struct {
+		name                 string
+		gaps                 []time.Duration
+		chatter, intentional []time.Duration
+	}{
+		{"empty", nil, nil, nil},
+		{"one gap", ms(150), nil, ms(150)},
+		{"two clusters", ms(160, 9, 150, 12), ms(9, 12), ms(150, 160)},
+		{"slow clusters", ms(50, 95), nil, ms(50, 95)},
+		// The jump from 8 to 30 is the largest, but 30 is too fast for a human
+		{"below the human floor", ms(8, 30, 120), ms(8, 30), ms(120)},
+		{"no jump", ms(45, 50, 60), nil, ms(45, 50, 60)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chatter, intentional := split(tt.gaps)
+			if len(chatter) == 0 {
+				chatter = nil
+			}
+			if len(intentional) == 0 {
+				intentional = nil
+			}
+			if !reflect.DeepEqual(chatter, tt.chatter) || !reflect.DeepEqual(intentional, tt.intentional) {
+				t.Errorf("split = %v, %v; want %v, %v", chatter, intentional, tt.chatter, tt.intentional)
+			}
+		})
+	}
+}
+
+func TestRecommend(t *testing.T) {
+	ms := time.Millisecond
+
+	tests := []struct {
+		name               string
+		chatter, doubleGap time.Duration
+		want               time.Duration
+	}{
+		{"no chatter", 0, 0, 30 * ms},
+		{"fast chatter keeps the least delay", 12 * ms, 0, 30 * ms},
+		{"margin rounded up", 33 * ms, 0, 50 * ms},
+		{"capped by double-clicks", 60 * ms, 100 * ms, 80 * ms},
+		{"never below the chatter", 90 * ms, 92 * ms, 95 * ms},
+		{"clamped to the maximum delay", 400 * ms, 0, 500 * ms},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := recommend(tt.chatter, tt.doubleGap); got != tt.want {
+				t.Errorf("recommend(%v, %v) = %v, want %v", tt.chatter, tt.doubleGap, got, tt.want)
+			}
+		})
+	}
+}
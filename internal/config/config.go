@@ -6,34 +6,262 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
+	"time"
+
+	"click-guardian/internal/filter"
+	"click-guardian/internal/hotkey"
+)
+
+// Delay limits accepted for the global and per-button delays
+const (
+	MinDelayMs = 5
+	MaxDelayMs = 500
+)
+
+// Key chatter threshold limits, for the gap between releasing a key and
+// pressing it again. Worn switches re-close within a few dozen milliseconds,
+// while even fast typists doubling a letter leave the key up for around
+// 100ms, hence the 40ms default in between. Below 5ms hardly any chatter is
+// caught; up to 200ms is allowed for badly worn keys (usually as a per-key
+// threshold), at the cost of fast deliberate repeats of that key.
+const (
+	MinKeyThresholdMs = 5
+	MaxKeyThresholdMs = 200
+)
+
+// Double-click window limits, around the range desktops offer
+const (
+	MinDoubleClickWindowMs = 100
+	MaxDoubleClickWindowMs = 1000
 )
 
 // Config holds the application configuration
 type Config struct {
-	DelayMs        int    `json:"delay_ms"`
-	LogLevel       string `json:"log_level"`
-	MaxLogLines    int    `json:"max_log_lines"`
-	WindowWidth    int    `json:"window_width"`
-	WindowHeight   int    `json:"window_height"`
-	MinimizeToTray bool   `json:"minimize_to_tray"`
+	DelayMs             int                       `json:"delay_ms"`
+	Buttons             map[string]ButtonSettings `json:"buttons"`
+	WheelFilterEnabled  bool                      `json:"wheel_filter_enabled"`
+	WheelWindowMs       int                       `json:"wheel_window_ms"`
+	FilterStrategy      string                    `json:"filter_strategy"`        // "strict" or "double-click"
+	DoubleClickWindowMs int                       `json:"double_click_window_ms"` // Longest release-to-press gap of a double-click
+	LogLevel            string                    `json:"log_level"`
+	MaxLogLines         int                       `json:"max_log_lines"`
+	WindowWidth         int                       `json:"window_width"`
+	WindowHeight        int                       `json:"window_height"`
+	MinimizeToTray      bool                      `json:"minimize_to_tray"`
+	MetricsEnabled      bool                      `json:"metrics_enabled"`
+	MetricsAddress      string                    `json:"metrics_address"`      // Loopback host:port of the Prometheus exporter
+	StatsRetentionDays  int                       `json:"stats_retention_days"` // 0 keeps the statistics history forever
+	HealthWarningScore  int                       `json:"health_warning_score"` // Notify when a button's health score drops below this, 0 disables
+	Profiles            []Profile                 `json:"profiles"`             // Per-application settings, the first match wins
+	Devices             map[string]DeviceSettings `json:"devices"`              // Per-device settings keyed by "vendor:product" ID
+	KeyboardEnabled     bool                      `json:"keyboard_enabled"`     // Suppress key chatter while protection is active
+	KeyThresholdMs      int                       `json:"key_threshold_ms"`     // Presses of a key closer than this are chatter
+	KeyThresholds       map[string]int            `json:"key_thresholds"`       // Per-key thresholds in ms keyed by key name, e.g. "E"
+	PauseHotkey         string                    `json:"pause_hotkey"`         // Global hotkey pausing and resuming protection, e.g. "Ctrl+Alt+P"
+	BypassModifier      string                    `json:"bypass_modifier"`      // Modifier letting clicks through while held, e.g. "Ctrl"
+}
+
+// ButtonSettings holds the settings of a single mouse button
+type ButtonSettings struct {
+	Enabled bool `json:"enabled"`
+	DelayMs int  `json:"delay_ms,omitempty"` // 0 uses the default delay
+}
+
+// DeviceSettings holds the settings of a single mouse or touchpad
+type DeviceSettings struct {
+	Name    string `json:"name"`               // Shown in the GUI, the ID identifies the device
+	Enabled bool   `json:"enabled"`            // False lets every click of the device through
+	DelayMs int    `json:"delay_ms,omitempty"` // Delay for every button, 0 keeps the configured delays
+}
+
+// Profile overrides the filter settings while a matching application is in
+// the foreground
+type Profile struct {
+	Name    string `json:"name"`
+	Match   string `json:"match"`              // Executable name or window class, case-insensitive
+	Enabled bool   `json:"enabled"`            // False lets every click of the application through
+	DelayMs int    `json:"delay_ms,omitempty"` // Delay for every button, 0 keeps the configured delays
 }
 
 // DefaultConfig returns a configuration with default values
 func DefaultConfig() *Config {
 	return &Config{
-		DelayMs:        50,
-		LogLevel:       "info",
-		MaxLogLines:    100,
-		WindowWidth:    500,
-		WindowHeight:   450,
-		MinimizeToTray: true,
+		DelayMs:             50,
+		Buttons:             defaultButtons(),
+		WheelFilterEnabled:  false,
+		WheelWindowMs:       100,
+		FilterStrategy:      filter.StrategyStrict.String(),
+		DoubleClickWindowMs: int(filter.DefaultDoubleClickWindow / time.Millisecond),
+		LogLevel:            "info",
+		MaxLogLines:         100,
+		WindowWidth:         500,
+		WindowHeight:        450,
+		MinimizeToTray:      true,
+		MetricsEnabled:      false,
+		MetricsAddress:      "127.0.0.1:9464",
+		StatsRetentionDays:  365,
+		HealthWarningScore:  50,
+		KeyboardEnabled:     false,
+		KeyThresholdMs:      int(filter.DefaultKeyThreshold / time.Millisecond),
+	}
+}
+
+// defaultButtons enables filtering for every button
+func defaultButtons() map[string]ButtonSettings {
+	buttons := make(map[string]ButtonSettings)
+	for _, b := range filter.Buttons() {
+		buttons[ButtonKey(b)] = ButtonSettings{Enabled: true}
+	}
+	return buttons
+}
+
+// ButtonKey returns the key a button is stored under in the config file
+func ButtonKey(b filter.Button) string {
+	return strings.ToLower(b.String())
+}
+
+// ButtonEnabled reports whether a button should be filtered
+func (c *Config) ButtonEnabled(b filter.Button) bool {
+	settings, ok := c.Buttons[ButtonKey(b)]
+	return !ok || settings.Enabled
+}
+
+// SetButtonEnabled enables or disables filtering for a button
+func (c *Config) SetButtonEnabled(b filter.Button, enabled bool) {
+	if c.Buttons == nil {
+		c.Buttons = make(map[string]ButtonSettings)
+	}
+	settings := c.Buttons[ButtonKey(b)]
+	settings.Enabled = enabled
+	c.Buttons[ButtonKey(b)] = settings
+}
+
+// ButtonDelayMs returns the delay of a button, falling back to DelayMs
+func (c *Config) ButtonDelayMs(b filter.Button) int {
+	if settings, ok := c.Buttons[ButtonKey(b)]; ok && settings.DelayMs > 0 {
+		return settings.DelayMs
+	}
+	return c.DelayMs
+}
+
+// SetButtonDelayMs sets the delay of a button; 0 restores the default delay
+func (c *Config) SetButtonDelayMs(b filter.Button, delayMs int) {
+	if c.Buttons == nil {
+		c.Buttons = make(map[string]ButtonSettings)
+	}
+	settings, ok := c.Buttons[ButtonKey(b)]
+	if !ok {
+		settings.Enabled = true
+	}
+	settings.DelayMs = delayMs
+	c.Buttons[ButtonKey(b)] = settings
+}
+
+// FilterConfig converts the settings into the filter engine configuration
+func (c *Config) FilterConfig() filter.Config {
+	cfg := filter.NewConfig(time.Duration(c.DelayMs) * time.Millisecond)
+	for _, b := range filter.Buttons() {
+		cfg.Buttons[b] = filter.ButtonConfig{
+			Enabled: c.ButtonEnabled(b),
+			Delay:   time.Duration(c.Buttons[ButtonKey(b)].DelayMs) * time.Millisecond,
+		}
+	}
+	cfg.Wheel = filter.WheelConfig{
+		Enabled: c.WheelFilterEnabled,
+		Window:  time.Duration(c.WheelWindowMs) * time.Millisecond,
+	}
+	cfg.Strategy, _ = filter.ParseStrategy(c.FilterStrategy)
+	cfg.DoubleClickWindow = time.Duration(c.DoubleClickWindowMs) * time.Millisecond
+	cfg.Devices = make(map[string]filter.DeviceConfig)
+	for id, d := range c.Devices {
+		cfg.Devices[id] = filter.DeviceConfig{
+			Enabled: d.Enabled,
+			Delay:   time.Duration(d.DelayMs) * time.Millisecond,
+		}
+	}
+	return cfg
+}
+
+// KeyFilterConfig converts the keyboard settings into the key filter
+// configuration
+func (c *Config) KeyFilterConfig() filter.KeyConfig {
+	cfg := filter.KeyConfig{
+		Threshold: time.Duration(c.KeyThresholdMs) * time.Millisecond,
+		Keys:      make(map[string]time.Duration),
+	}
+	for name, ms := range c.KeyThresholds {
+		cfg.Keys[strings.ToUpper(name)] = time.Duration(ms) * time.Millisecond
+	}
+	return cfg
+}
+
+// HotkeyConfig converts the hotkey settings for the hotkey hook
+func (c *Config) HotkeyConfig() hotkey.Config {
+	// Both settings are validated when the config is loaded
+	toggle, _ := hotkey.Parse(c.PauseHotkey)
+	bypass, _ := hotkey.ParseModifier(c.BypassModifier)
+	return hotkey.Config{Toggle: toggle, Bypass: bypass}
+}
+
+// DeviceEnabled reports whether a device should be filtered; devices
+// without settings are
+func (c *Config) DeviceEnabled(id string) bool {
+	settings, ok := c.Devices[id]
+	return !ok || settings.Enabled
+}
+
+// SetDeviceSettings stores the settings of a device
+func (c *Config) SetDeviceSettings(id string, settings DeviceSettings) {
+	if c.Devices == nil {
+		c.Devices = make(map[string]DeviceSettings)
+	}
+	c.Devices[id] = settings
+}
+
+// Summary describes the settings of the profile
+func (p Profile) Summary() string {
+	switch {
+	case !p.Enabled:
+		return "protection off"
+	case p.DelayMs > 0:
+		return fmt.Sprintf("%d ms delay", p.DelayMs)
+	default:
+		return "configured delays"
 	}
 }
 
+// ProfileFilterConfig returns the filter engine configuration used while
+// profile p is active. The profile takes precedence over the button and
+// device settings: its delay replaces all of theirs.
+func (c *Config) ProfileFilterConfig(p Profile) filter.Config {
+	cfg := c.FilterConfig()
+	for b, bc := range cfg.Buttons {
+		if !p.Enabled {
+			bc.Enabled = false
+		}
+		if p.DelayMs > 0 {
+			bc.Delay = 0
+		}
+		cfg.Buttons[b] = bc
+	}
+	if p.DelayMs > 0 {
+		cfg.Delay = time.Duration(p.DelayMs) * time.Millisecond
+		for id, dc := range cfg.Devices {
+			dc.Delay = 0
+			cfg.Devices[id] = dc
+		}
+	}
+	if !p.Enabled {
+		cfg.Wheel.Enabled = false
+	}
+	return cfg
+}
+
 // ValidateDelay validates the delay value
 func (c *Config) ValidateDelay() error {
-	if c.DelayMs < 1 || c.DelayMs > 500 {
-		return fmt.Errorf("delay must be between 1 and 500 milliseconds")
+	if c.DelayMs < MinDelayMs || c.DelayMs > MaxDelayMs {
+		return fmt.Errorf("delay must be between %d and %d milliseconds", MinDelayMs, MaxDelayMs)
 	}
 	return nil
 }
@@ -49,8 +277,8 @@ func ParseDelay(delayStr string) (int, error) {
 		return 0, fmt.Errorf("invalid delay format: %v", err)
 	}
 
-	if delayMs < 1 || delayMs > 500 {
-		return 0, fmt.Errorf("delay must be between 1 and 500 milliseconds")
+	if delayMs < MinDelayMs || delayMs > MaxDelayMs {
+		return 0, fmt.Errorf("delay must be between %d and %d milliseconds", MinDelayMs, MaxDelayMs)
 	}
 
 	return delayMs, nil
@@ -58,6 +286,15 @@ func ParseDelay(delayStr string) (int, error) {
 
 // GetConfigPath returns the path to the configuration file
 func GetConfigPath() (string, error) {
+	appConfigDir, err := GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(appConfigDir, "config.json"), nil
+}
+
+// GetConfigDir returns the application config directory, creating it if needed
+func GetConfigDir() (string, error) {
 	configDir, err := os.UserConfigDir()
 	if err != nil {
 		return "", fmt.Errorf("failed to get user config directory: %v", err)
@@ -67,8 +304,21 @@ func GetConfigPath() (string, error) {
 	if err := os.MkdirAll(appConfigDir, 0o755); err != nil {
 		return "", fmt.Errorf("failed to create app config directory: %v", err)
 	}
+	return appConfigDir, nil
+}
 
-	return filepath.Join(appConfigDir, "config.json"), nil
+// GetTracesDir returns the directory event traces are recorded to
+func GetTracesDir() (string, error) {
+	appConfigDir, err := GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	tracesDir := filepath.Join(appConfigDir, "traces")
+	if err := os.MkdirAll(tracesDir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create traces directory: %v", err)
+	}
+	return tracesDir, nil
 }
 
 // LoadConfig loads configuration from file, or returns default if file doesn't exist
@@ -87,15 +337,39 @@ func LoadConfig() *Config {
 		return DefaultConfig()
 	}
 
-	var config Config
-	if err := json.Unmarshal(data, &config); err != nil {
+	// Settings missing from older config files keep their defaults
+	config := DefaultConfig()
+	if err := json.Unmarshal(data, config); err != nil {
 		return DefaultConfig()
 	}
 
 	// Validate loaded config and use defaults for invalid values
-	if config.DelayMs < 5 || config.DelayMs > 500 {
+	if config.DelayMs < MinDelayMs || config.DelayMs > MaxDelayMs {
 		config.DelayMs = DefaultConfig().DelayMs
 	}
+	if config.WheelWindowMs < 10 || config.WheelWindowMs > 500 {
+		config.WheelWindowMs = DefaultConfig().WheelWindowMs
+	}
+	if _, ok := filter.ParseStrategy(config.FilterStrategy); !ok {
+		config.FilterStrategy = DefaultConfig().FilterStrategy
+	}
+	if config.DoubleClickWindowMs < MinDoubleClickWindowMs || config.DoubleClickWindowMs > MaxDoubleClickWindowMs {
+		config.DoubleClickWindowMs = DefaultConfig().DoubleClickWindowMs
+	}
+	if config.KeyThresholdMs < MinKeyThresholdMs || config.KeyThresholdMs > MaxKeyThresholdMs {
+		config.KeyThresholdMs = DefaultConfig().KeyThresholdMs
+	}
+	for name, ms := range config.KeyThresholds {
+		if ms < MinKeyThresholdMs || ms > MaxKeyThresholdMs {
+			delete(config.KeyThresholds, name)
+		}
+	}
+	if _, err := hotkey.Parse(config.PauseHotkey); err != nil {
+		config.PauseHotkey = ""
+	}
+	if _, err := hotkey.ParseModifier(config.BypassModifier); err != nil {
+		config.BypassModifier = ""
+	}
 	if config.MaxLogLines <= 0 {
 		config.MaxLogLines = DefaultConfig().MaxLogLines
 	}
@@ -105,11 +379,45 @@ func LoadConfig() *Config {
 	if config.WindowHeight <= 0 {
 		config.WindowHeight = DefaultConfig().WindowHeight
 	}
+	if config.StatsRetentionDays < 0 {
+		config.StatsRetentionDays = DefaultConfig().StatsRetentionDays
+	}
+	if config.HealthWarningScore < 0 || config.HealthWarningScore > 100 {
+		config.HealthWarningScore = DefaultConfig().HealthWarningScore
+	}
+	if config.MetricsAddress == "" {
+		config.MetricsAddress = DefaultConfig().MetricsAddress
+	}
 	if config.LogLevel == "" {
 		config.LogLevel = DefaultConfig().LogLevel
 	}
+	if config.Buttons == nil {
+		config.Buttons = make(map[string]ButtonSettings)
+	}
+	for key, settings := range defaultButtons() {
+		if _, ok := config.Buttons[key]; !ok {
+			config.Buttons[key] = settings
+		}
+	}
+	for key, settings := range config.Buttons {
+		if settings.DelayMs != 0 && (settings.DelayMs < MinDelayMs || settings.DelayMs > MaxDelayMs) {
+			settings.DelayMs = 0
+			config.Buttons[key] = settings
+		}
+	}
+	for id, settings := range config.Devices {
+		if settings.DelayMs != 0 && (settings.DelayMs < MinDelayMs || settings.DelayMs > MaxDelayMs) {
+			settings.DelayMs = 0
+			config.Devices[id] = settings
+		}
+	}
+	for i, profile := range config.Profiles {
+		if profile.DelayMs != 0 && (profile.DelayMs < MinDelayMs || profile.DelayMs > MaxDelayMs) {
+			config.Profiles[i].DelayMs = 0
+		}
+	}
 
-	return &config
+	return config
 }
 
 // Save saves the configuration to file
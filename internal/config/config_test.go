@@ -0,0 +1,96 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"click-guardian/internal/filter"
+)
+
+func TestProfileFilterConfig(t *testing.T) {
+	const mouse, other = "046d:c077", "1532:0084"
+
+	c := DefaultConfig()
+	c.DelayMs = 50
+	c.WheelFilterEnabled = true
+	c.SetButtonDelayMs(filter.ButtonRight, 80)
+	c.Devices = map[string]DeviceSettings{
+		mouse: {Enabled: true, DelayMs: 90},
+		other: {Enabled: false},
+	}
+	ms := time.Millisecond
+
+	tests := []struct {
+		name    string
+		profile Profile
+		device  string
+		left    time.Duration // Delay of the left button, zero when it is not filtered
+		right   time.Duration
+		wheel   bool
+	}{
+		{
+			name:    "configured delays",
+			profile: Profile{Enabled: true},
+			left:    50 * ms,
+			right:   80 * ms,
+			wheel:   true,
+		},
+		{
+			name:    "device delay without a profile delay",
+			profile: Profile{Enabled: true},
+			device:  mouse,
+			left:    90 * ms,
+			right:   90 * ms,
+			wheel:   true,
+		},
+		{
+			name:    "profile delay",
+			profile: Profile{Enabled: true, DelayMs: 120},
+			left:    120 * ms,
+			right:   120 * ms,
+			wheel:   true,
+		},
+		{
+			name:    "profile delay over the device delay",
+			profile: Profile{Enabled: true, DelayMs: 120},
+			device:  mouse,
+			left:    120 * ms,
+			right:   120 * ms,
+			wheel:   true,
+		},
+		{
+			name:    "disabled profile",
+			profile: Profile{Enabled: false, DelayMs: 120},
+			device:  mouse,
+		},
+		{
+			// A profile does not enable a device the user turned off
+			name:    "disabled device",
+			profile: Profile{Enabled: true, DelayMs: 120},
+			device:  other,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := c.ProfileFilterConfig(tt.profile).ForDevice(tt.device)
+			delay := func(b filter.Button) time.Duration {
+				if !cfg.Enabled(b) {
+					return 0
+				}
+				return cfg.DelayFor(b)
+			}
+			if left, right := delay(filter.ButtonLeft), delay(filter.ButtonRight); left != tt.left || right != tt.right {
+				t.Errorf("delays left %v, right %v; want %v, %v", left, right, tt.left, tt.right)
+			}
+			if cfg.Wheel.Enabled != tt.wheel {
+				t.Errorf("wheel filter %v, want %v", cfg.Wheel.Enabled, tt.wheel)
+			}
+		})
+	}
+
+	// The configured settings stay untouched
+	if got := c.FilterConfig().ForDevice(mouse).DelayFor(filter.ButtonLeft); got != 90*ms {
+		t.Errorf("device delay after building profiles = %v, want 90ms", got)
+	}
+}
@@ -0,0 +1,112 @@
+package control
+
+import (
+	"errors"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+)
+
+var errNotRunning = errors.New("click guardian is not running")
+
+// IsNotRunning reports whether err means no instance is listening
+func IsNotRunning(err error) bool {
+	return errors.Is(err, errNotRunning)
+}
+
+// Client talks to a running instance over the control socket
+type Client struct {
+	rpc *rpc.Client
+}
+
+// Dial connects to the running instance
+func Dial() (*Client, error) {
+	path, err := SocketPath()
+	if err != nil {
+		return nil, err
+	}
+	conn, err := dial(path)
+	if err != nil {
+		return nil, errNotRunning
+	}
+	return &Client{rpc: jsonrpc.NewClient(conn)}, nil
+}
+
+// Close closes the connection
+func (c *Client) Close() error {
+	return c.rpc.Close()
+}
+
+func (c *Client) call(method string, args, reply interface{}) error {
+	return c.rpc.Call(serviceName+"."+method, args, reply)
+}
+
+// Status returns the state of the instance
+func (c *Client) Status() (Status, error) {
+	var reply Status
+	err := c.call("Status", Empty{}, &reply)
+	return reply, err
+}
+
+// Start enables protection
+func (c *Client) Start() (Status, error) {
+	var reply Status
+	err := c.call("Start", Empty{}, &reply)
+	return reply, err
+}
+
+// Stop disables protection
+func (c *Client) Stop() (Status, error) {
+	var reply Status
+	err := c.call("Stop", Empty{}, &reply)
+	return reply, err
+}
+
+// Snooze stops protection for the given number of minutes, after which
+// the instance starts it again
+func (c *Client) Snooze(minutes int) (Status, error) {
+	var reply Status
+	err := c.call("Snooze", SnoozeArgs{Minutes: minutes}, &reply)
+	return reply, err
+}
+
+// Activate forwards the flags of a second launch to the instance
+func (c *Client) Activate(args ActivateArgs) (Status, error) {
+	var reply Status
+	err := c.call("Activate", args, &reply)
+	return reply, err
+}
+
+// GetDelay returns the global delay in milliseconds
+func (c *Client) GetDelay() (int, error) {
+	var reply DelayReply
+	err := c.call("GetDelay", Empty{}, &reply)
+	return reply.DelayMs, err
+}
+
+// SetDelay changes the global delay, also of active protection
+func (c *Client) SetDelay(ms int) (int, error) {
+	var reply DelayReply
+	err := c.call("SetDelay", DelayArgs{DelayMs: ms}, &reply)
+	return reply.DelayMs, err
+}
+
+// Stats returns the statistics of the current session
+func (c *Client) Stats() (Stats, error) {
+	var reply Stats
+	err := c.call("Stats", Empty{}, &reply)
+	return reply, err
+}
+
+// Health returns the health score of every button
+func (c *Client) Health() (HealthReply, error) {
+	var reply HealthReply
+	err := c.call("Health", Empty{}, &reply)
+	return reply, err
+}
+
+// Events returns events published after seq, waiting up to timeoutMs for one
+func (c *Client) Events(after uint64, timeoutMs int) (EventsReply, error) {
+	var reply EventsReply
+	err := c.call("Events", EventsArgs{After: after, TimeoutMs: timeoutMs}, &reply)
+	return reply, err
+}
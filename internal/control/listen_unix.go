@@ -0,0 +1,78 @@
+//go:build !windows
+
+package control
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+
+	"click-guardian/internal/config"
+)
+
+// SocketPath returns the path of the control socket. It lies in a folder
+// only the current user can enter, so the socket is never reachable by
+// others, not even before its permissions are set: the runtime folder of
+// the session, or a private folder in the config folder without one.
+func SocketPath() (string, error) {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, "click-guardian.sock"), nil
+	}
+
+	configDir, err := config.GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(configDir, "run")
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", fmt.Errorf("failed to create control socket folder: %v", err)
+	}
+	// MkdirAll keeps the permissions of an existing folder
+	if err := os.Chmod(dir, 0o700); err != nil {
+		return "", fmt.Errorf("failed to restrict control socket folder to the current user: %v", err)
+	}
+	return filepath.Join(dir, "control.sock"), nil
+}
+
+// unixListener serves a Unix domain socket that only its owner can connect to
+type unixListener struct {
+	listener net.Listener
+	path     string
+}
+
+func listen(path string) (listener, error) {
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		// A socket left behind by a crashed instance refuses connections
+		if conn, dialErr := net.Dial("unix", path); dialErr == nil {
+			conn.Close()
+			return nil, ErrAlreadyRunning
+		}
+		os.Remove(path)
+		if l, err = net.Listen("unix", path); err != nil {
+			return nil, fmt.Errorf("failed to create control socket: %v", err)
+		}
+	}
+	if err := os.Chmod(path, 0o600); err != nil {
+		l.Close()
+		os.Remove(path)
+		return nil, fmt.Errorf("failed to restrict control socket to the current user: %v", err)
+	}
+	return &unixListener{listener: l, path: path}, nil
+}
+
+func (l *unixListener) Accept() (io.ReadWriteCloser, error) {
+	return l.listener.Accept()
+}
+
+func (l *unixListener) Close() error {
+	err := l.listener.Close()
+	os.Remove(l.path)
+	return err
+}
+
+func dial(path string) (io.ReadWriteCloser, error) {
+	return net.Dial("unix", path)
+}
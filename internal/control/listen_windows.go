@@ -0,0 +1,140 @@
+//go:build windows
+
+package control
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+const pipeBufferSize = 4096
+
+// SocketPath returns the name of the control pipe. It contains the SID of
+// the current user so that every user session gets its own instance.
+func SocketPath() (string, error) {
+	sid, err := currentUserSID()
+	if err != nil {
+		return "", err
+	}
+	return `\\.\pipe\ClickGuardian-control-` + sid, nil
+}
+
+// currentUserSID returns the SID of the user running the process
+func currentUserSID() (string, error) {
+	user, err := windows.GetCurrentProcessToken().GetTokenUser()
+	if err != nil {
+		return "", fmt.Errorf("failed to get current user: %v", err)
+	}
+	return user.User.Sid.String(), nil
+}
+
+// pipeListener serves a named pipe whose DACL only admits the current user.
+// One instance always waits for the next client.
+type pipeListener struct {
+	name string
+	sa   *windows.SecurityAttributes
+
+	mu     sync.Mutex
+	next   windows.Handle // Instance waiting for the next client
+	closed bool
+}
+
+func listen(name string) (listener, error) {
+	sid, err := currentUserSID()
+	if err != nil {
+		return nil, err
+	}
+	// Protected DACL granting full access to the current user only
+	sd, err := windows.SecurityDescriptorFromString("D:P(A;;GA;;;" + sid + ")")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create control pipe security descriptor: %v", err)
+	}
+	l := &pipeListener{
+		name: name,
+		sa: &windows.SecurityAttributes{
+			Length:             uint32(unsafe.Sizeof(windows.SecurityAttributes{})),
+			SecurityDescriptor: sd,
+		},
+	}
+
+	// The first instance fails with access denied while another process
+	// owns the pipe. Pipes disappear with their process, nothing is left
+	// behind by a crash.
+	l.next, err = l.createInstance(windows.FILE_FLAG_FIRST_PIPE_INSTANCE)
+	if errors.Is(err, windows.ERROR_ACCESS_DENIED) {
+		return nil, ErrAlreadyRunning
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create control pipe: %v", err)
+	}
+	return l, nil
+}
+
+func (l *pipeListener) createInstance(flags uint32) (windows.Handle, error) {
+	name, err := windows.UTF16PtrFromString(l.name)
+	if err != nil {
+		return windows.InvalidHandle, err
+	}
+	return windows.CreateNamedPipe(name,
+		windows.PIPE_ACCESS_DUPLEX|flags,
+		windows.PIPE_TYPE_BYTE|windows.PIPE_WAIT|windows.PIPE_REJECT_REMOTE_CLIENTS,
+		windows.PIPE_UNLIMITED_INSTANCES, pipeBufferSize, pipeBufferSize, 0, l.sa)
+}
+
+func (l *pipeListener) Accept() (io.ReadWriteCloser, error) {
+	l.mu.Lock()
+	h := l.next
+	closed := l.closed
+	l.mu.Unlock()
+	if closed {
+		return nil, net.ErrClosed
+	}
+
+	err := windows.ConnectNamedPipe(h, nil)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return nil, net.ErrClosed
+	}
+	if err != nil && !errors.Is(err, windows.ERROR_PIPE_CONNECTED) {
+		return nil, err
+	}
+	// Wait for the following client before handing this one over
+	if l.next, err = l.createInstance(0); err != nil {
+		l.next = windows.InvalidHandle
+		l.closed = true
+		windows.CloseHandle(h)
+		return nil, fmt.Errorf("failed to create control pipe: %v", err)
+	}
+	return os.NewFile(uintptr(h), l.name), nil
+}
+
+func (l *pipeListener) Close() error {
+	l.mu.Lock()
+	if l.closed {
+		l.mu.Unlock()
+		return nil
+	}
+	l.closed = true
+	h := l.next
+	l.next = windows.InvalidHandle
+	l.mu.Unlock()
+
+	// Connecting wakes an Accept blocked on the instance
+	if conn, err := dial(l.name); err == nil {
+		conn.Close()
+	}
+	return windows.CloseHandle(h)
+}
+
+func dial(name string) (io.ReadWriteCloser, error) {
+	return os.OpenFile(name, os.O_RDWR, 0)
+}
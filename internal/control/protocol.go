@@ -0,0 +1,153 @@
+package control
+
+import (
+	"strings"
+	"time"
+
+	"click-guardian/internal/filter"
+	"click-guardian/internal/health"
+)
+
+// serviceName is the net/rpc service the control methods are registered under
+const serviceName = "Control"
+
+// Empty is used for methods without arguments
+type Empty struct{}
+
+// Status describes the state of a running instance
+type Status struct {
+	Running       bool   `json:"running"`
+	DelayMs       int    `json:"delay_ms"`
+	BlockedClicks int    `json:"blocked_clicks"`
+	Version       string `json:"version"`
+	Mode          string `json:"mode"` // "gui" or "headless"
+
+	// SnoozedUntil is when paused protection starts again, zero unless snoozed
+	SnoozedUntil time.Time `json:"snoozed_until,omitzero"`
+}
+
+// ActivateArgs carries the flags a second launch forwards to the running instance
+type ActivateArgs struct {
+	ShowWindow      bool `json:"show_window"`
+	StartProtection bool `json:"start_protection"`
+}
+
+// DelayArgs carries a new delay for SetDelay
+type DelayArgs struct {
+	DelayMs int `json:"delay_ms"`
+}
+
+// DelayReply returns the delay now in effect
+type DelayReply struct {
+	DelayMs int `json:"delay_ms"`
+}
+
+// MaxSnoozeMinutes is the longest protection can be snoozed for
+const MaxSnoozeMinutes = 24 * 60
+
+// SnoozeArgs carries the length of a pause for Snooze
+type SnoozeArgs struct {
+	Minutes int `json:"minutes"`
+}
+
+// ButtonStats counts the decisions for one button
+type ButtonStats struct {
+	Allowed int            `json:"allowed"`
+	Blocked map[string]int `json:"blocked"` // Per block reason
+}
+
+// Stats is the JSON form of filter.Stats
+type Stats struct {
+	Buttons      map[string]ButtonStats `json:"buttons"`
+	TotalAllowed int                    `json:"total_allowed"`
+	TotalBlocked int                    `json:"total_blocked"`
+	WheelAllowed int                    `json:"wheel_allowed"`
+	WheelBlocked int                    `json:"wheel_blocked"`
+}
+
+// NewStats converts filter statistics for the wire
+func NewStats(s filter.Stats) Stats {
+	out := Stats{
+		Buttons:      make(map[string]ButtonStats),
+		TotalAllowed: s.TotalAllowed(),
+		TotalBlocked: s.TotalBlockedClicks(),
+		WheelAllowed: s.WheelAllowed,
+		WheelBlocked: s.WheelBlocked,
+	}
+	for _, b := range filter.Buttons() {
+		bs := ButtonStats{Allowed: s.Allowed[b], Blocked: make(map[string]int)}
+		for reason, n := range s.Blocked[b] {
+			bs.Blocked[reason.String()] = n
+		}
+		out.Buttons[strings.ToLower(b.String())] = bs
+	}
+	return out
+}
+
+// ButtonHealth is the JSON form of a health.Report
+type ButtonHealth struct {
+	Button         string  `json:"button"`
+	Score          int     `json:"score"` // -1 when not enough clicks were recorded
+	Level          string  `json:"level"`
+	Clicks         int     `json:"clicks"`
+	BounceRate     float64 `json:"bounce_rate"`
+	SlowBounceRate float64 `json:"slow_bounce_rate"`
+	Trend          float64 `json:"trend"`
+	Recommendation string  `json:"recommendation"`
+}
+
+// HealthReply returns the health of every button
+type HealthReply struct {
+	WindowDays int            `json:"window_days"`
+	Buttons    []ButtonHealth `json:"buttons"`
+}
+
+// NewHealth converts health reports for the wire
+func NewHealth(reports []health.Report) HealthReply {
+	out := HealthReply{WindowDays: health.WindowDays, Buttons: make([]ButtonHealth, 0, len(reports))}
+	for _, r := range reports {
+		out.Buttons = append(out.Buttons, ButtonHealth{
+			Button:         strings.ToLower(r.Button.String()),
+			Score:          r.Score,
+			Level:          r.Level.String(),
+			Clicks:         r.Clicks,
+			BounceRate:     r.BounceRate,
+			SlowBounceRate: r.SlowBounceRate,
+			Trend:          r.Trend,
+			Recommendation: r.Recommendation(),
+		})
+	}
+	return out
+}
+
+// Event is the JSON form of a hook event
+type Event struct {
+	Seq        uint64    `json:"seq"`
+	Time       time.Time `json:"time"`
+	Type       string    `json:"type"`
+	Button     string    `json:"button,omitempty"`
+	Kind       string    `json:"kind,omitempty"`
+	Delta      int       `json:"delta,omitempty"`
+	Key        string    `json:"key,omitempty"`
+	Allowed    bool      `json:"allowed"`
+	Reason     string    `json:"reason,omitempty"`
+	IntervalMs float64   `json:"interval_ms,omitempty"`
+	DelayMs    float64   `json:"delay_ms,omitempty"`
+	Device     string    `json:"device,omitempty"`
+	DeviceID   string    `json:"device_id,omitempty"` // Vendor and product ID of the device
+	Error      string    `json:"error,omitempty"`
+	Fatal      bool      `json:"fatal,omitempty"`   // The error stopped protection
+	Message    string    `json:"message,omitempty"` // Log line shown by the application
+}
+
+// EventsArgs asks for events published after a sequence number
+type EventsArgs struct {
+	After     uint64 `json:"after"`
+	TimeoutMs int    `json:"timeout_ms"` // Wait up to this long when no event is pending
+}
+
+// EventsReply returns pending events and the sequence number to continue from
+type EventsReply struct {
+	Events []Event `json:"events"`
+	Next   uint64  `json:"next"`
+}
@@ -0,0 +1,287 @@
+package control
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"sync"
+	"time"
+
+	"click-guardian/internal/config"
+	"click-guardian/internal/filter"
+	"click-guardian/internal/health"
+	"click-guardian/internal/hooks"
+)
+
+const (
+	eventBacklog   = 256              // Events kept for clients that poll
+	maxEventsReply = 100              // Events returned per poll
+	maxEventsWait  = 30 * time.Second // Longest a poll may wait for new events
+)
+
+// ErrAlreadyRunning is returned by Listen when another instance owns the socket
+var ErrAlreadyRunning = errors.New("another instance is already running")
+
+// Controller is implemented by the GUI and the headless runner
+type Controller interface {
+	Status() Status
+	StartProtection() error
+	StopProtection() error
+	SetDelay(ms int) error
+	Snooze(d time.Duration) error
+	Stats() filter.Stats
+	Health() ([]health.Report, error)
+	ShowWindow() error
+}
+
+// listener accepts connections on the control socket or pipe of the platform
+type listener interface {
+	Accept() (io.ReadWriteCloser, error)
+	Close() error
+}
+
+// Server exposes a Controller over a local socket using JSON-RPC. Owning the
+// socket also marks the single running instance.
+type Server struct {
+	controller Controller
+	listener   listener
+
+	mu      sync.Mutex // Serializes control calls
+	eventMu sync.Mutex
+	events  []Event
+	nextSeq uint64
+	notify  chan struct{} // Closed and replaced whenever an event is published
+}
+
+// Listen claims the control socket. It returns ErrAlreadyRunning if another
+// instance is listening; requests are only served once Serve is called.
+func Listen() (*Server, error) {
+	path, err := SocketPath()
+	if err != nil {
+		return nil, err
+	}
+
+	listener, err := listen(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Server{
+		listener: listener,
+		nextSeq:  1,
+		notify:   make(chan struct{}),
+	}, nil
+}
+
+// Serve starts answering requests with controller
+func (s *Server) Serve(controller Controller) error {
+	s.controller = controller
+
+	rpcServer := rpc.NewServer()
+	if err := rpcServer.RegisterName(serviceName, &service{server: s}); err != nil {
+		return fmt.Errorf("failed to register control service: %v", err)
+	}
+
+	go func() {
+		for {
+			conn, err := s.listener.Accept()
+			if err != nil {
+				return
+			}
+			go rpcServer.ServeCodec(jsonrpc.NewServerCodec(conn))
+		}
+	}()
+	return nil
+}
+
+// Close stops accepting requests and removes the socket
+func (s *Server) Close() error {
+	return s.listener.Close()
+}
+
+// Publish makes a hook event available to clients tailing events. message is
+// the log line the application shows for it, if any.
+func (s *Server) Publish(ev hooks.Event, message string) {
+	e := Event{
+		Time:       ev.Time,
+		Type:       ev.Type.String(),
+		Delta:      ev.Delta,
+		Key:        ev.Key,
+		Allowed:    ev.Allowed,
+		IntervalMs: float64(ev.Interval) / float64(time.Millisecond),
+		DelayMs:    float64(ev.Delay) / float64(time.Millisecond),
+		Device:     ev.Device,
+		DeviceID:   ev.DeviceID,
+		Message:    message,
+	}
+	switch ev.Type {
+	case hooks.EventButton:
+		e.Button = ev.Button.String()
+		e.Kind = ev.Kind.String()
+	case hooks.EventDrag, hooks.EventDelayAdjusted:
+		e.Button = ev.Button.String()
+	}
+	if !ev.Allowed && ev.Reason != filter.ReasonNone {
+		e.Reason = ev.Reason.String()
+	}
+	if ev.Err != nil {
+		e.Error = ev.Err.Error()
+		e.Fatal = ev.Fatal
+	}
+
+	s.eventMu.Lock()
+	defer s.eventMu.Unlock()
+	e.Seq = s.nextSeq
+	s.nextSeq++
+	s.events = append(s.events, e)
+	if len(s.events) > eventBacklog {
+		s.events = s.events[len(s.events)-eventBacklog:]
+	}
+	close(s.notify)
+	s.notify = make(chan struct{})
+}
+
+// eventsAfter returns pending events after seq and a channel that is closed
+// when the next event arrives
+func (s *Server) eventsAfter(seq uint64) ([]Event, uint64, <-chan struct{}) {
+	s.eventMu.Lock()
+	defer s.eventMu.Unlock()
+
+	var pending []Event
+	for _, e := range s.events {
+		if e.Seq > seq {
+			pending = append(pending, e)
+			if len(pending) == maxEventsReply {
+				break
+			}
+		}
+	}
+	next := seq
+	if len(pending) > 0 {
+		next = pending[len(pending)-1].Seq
+	} else if seq >= s.nextSeq {
+		// The client comes from an earlier run, start over
+		next = s.nextSeq - 1
+	}
+	return pending, next, s.notify
+}
+
+// service holds the methods exported over JSON-RPC
+type service struct {
+	server *Server
+}
+
+func (s *service) Status(_ Empty, reply *Status) error {
+	s.server.mu.Lock()
+	defer s.server.mu.Unlock()
+	*reply = s.server.controller.Status()
+	return nil
+}
+
+func (s *service) Start(_ Empty, reply *Status) error {
+	s.server.mu.Lock()
+	defer s.server.mu.Unlock()
+	if err := s.server.controller.StartProtection(); err != nil {
+		return err
+	}
+	*reply = s.server.controller.Status()
+	return nil
+}
+
+func (s *service) Stop(_ Empty, reply *Status) error {
+	s.server.mu.Lock()
+	defer s.server.mu.Unlock()
+	if err := s.server.controller.StopProtection(); err != nil {
+		return err
+	}
+	*reply = s.server.controller.Status()
+	return nil
+}
+
+func (s *service) GetDelay(_ Empty, reply *DelayReply) error {
+	s.server.mu.Lock()
+	defer s.server.mu.Unlock()
+	reply.DelayMs = s.server.controller.Status().DelayMs
+	return nil
+}
+
+func (s *service) SetDelay(args DelayArgs, reply *DelayReply) error {
+	if args.DelayMs < config.MinDelayMs || args.DelayMs > config.MaxDelayMs {
+		return fmt.Errorf("delay must be between %d and %d ms", config.MinDelayMs, config.MaxDelayMs)
+	}
+
+	s.server.mu.Lock()
+	defer s.server.mu.Unlock()
+	if err := s.server.controller.SetDelay(args.DelayMs); err != nil {
+		return err
+	}
+	reply.DelayMs = s.server.controller.Status().DelayMs
+	return nil
+}
+
+func (s *service) Snooze(args SnoozeArgs, reply *Status) error {
+	if args.Minutes < 1 || args.Minutes > MaxSnoozeMinutes {
+		return fmt.Errorf("snooze must last between 1 and %d minutes", MaxSnoozeMinutes)
+	}
+
+	s.server.mu.Lock()
+	defer s.server.mu.Unlock()
+	if err := s.server.controller.Snooze(time.Duration(args.Minutes) * time.Minute); err != nil {
+		return err
+	}
+	*reply = s.server.controller.Status()
+	return nil
+}
+
+func (s *service) Activate(args ActivateArgs, reply *Status) error {
+	s.server.mu.Lock()
+	defer s.server.mu.Unlock()
+	if args.ShowWindow {
+		if err := s.server.controller.ShowWindow(); err != nil {
+			return err
+		}
+	}
+	if args.StartProtection {
+		if err := s.server.controller.StartProtection(); err != nil {
+			return err
+		}
+	}
+	*reply = s.server.controller.Status()
+	return nil
+}
+
+func (s *service) Stats(_ Empty, reply *Stats) error {
+	*reply = NewStats(s.server.controller.Stats())
+	return nil
+}
+
+func (s *service) Health(_ Empty, reply *HealthReply) error {
+	reports, err := s.server.controller.Health()
+	if err != nil {
+		return err
+	}
+	*reply = NewHealth(reports)
+	return nil
+}
+
+func (s *service) Events(args EventsArgs, reply *EventsReply) error {
+	wait := min(time.Duration(args.TimeoutMs)*time.Millisecond, maxEventsWait)
+
+	events, next, notify := s.server.eventsAfter(args.After)
+	if len(events) == 0 && wait > 0 {
+		select {
+		case <-notify:
+			events, next, _ = s.server.eventsAfter(next)
+		case <-time.After(wait):
+		}
+	}
+
+	reply.Events = events
+	reply.Next = next
+	if reply.Events == nil {
+		reply.Events = []Event{}
+	}
+	return nil
+}
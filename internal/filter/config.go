@@ -0,0 +1,133 @@
+package filter
+
+import (
+	"strings"
+	"time"
+)
+
+// DefaultDoubleClickWindow matches the default double-click time of the
+// common desktops
+const DefaultDoubleClickWindow = 500 * time.Millisecond
+
+// Strategy selects how presses within the delay are treated
+type Strategy int
+
+const (
+	StrategyStrict      Strategy = iota // Block every press within the delay
+	StrategyDoubleClick                 // Let intentional double-clicks through
+)
+
+// Strategies returns every filter strategy
+func Strategies() []Strategy {
+	return []Strategy{StrategyStrict, StrategyDoubleClick}
+}
+
+// ParseStrategy looks up a strategy by its name, ignoring case
+func ParseStrategy(name string) (Strategy, bool) {
+	for _, s := range Strategies() {
+		if strings.EqualFold(s.String(), name) {
+			return s, true
+		}
+	}
+	return 0, false
+}
+
+// String returns the name the strategy is stored and shown under
+func (s Strategy) String() string {
+	switch s {
+	case StrategyStrict:
+		return "strict"
+	case StrategyDoubleClick:
+		return "double-click"
+	default:
+		return "unknown"
+	}
+}
+
+// ButtonConfig holds the settings of a single button
+type ButtonConfig struct {
+	Enabled bool          // Disabled buttons pass through unfiltered
+	Delay   time.Duration // Overrides Config.Delay when non-zero
+}
+
+// DeviceConfig holds the settings of a single input device
+type DeviceConfig struct {
+	Enabled bool          // Disabled devices pass through unfiltered
+	Delay   time.Duration // Replaces the delay of every button when non-zero
+}
+
+// WheelConfig holds the scroll wheel filter settings
+type WheelConfig struct {
+	Enabled bool
+	Window  time.Duration // Ticks closer than this count as continuous scrolling
+}
+
+// Config holds the settings the Engine filters with
+type Config struct {
+	Delay    time.Duration
+	Buttons  map[Button]ButtonConfig // Buttons missing from the map are enabled
+	Wheel    WheelConfig
+	Strategy Strategy
+
+	// Devices overrides the settings per device ID; devices missing from
+	// the map use the settings above
+	Devices map[string]DeviceConfig
+
+	// DoubleClickWindow is the longest release-to-press gap of an
+	// intentional double-click under StrategyDoubleClick
+	DoubleClickWindow time.Duration
+}
+
+// NewConfig returns a configuration that filters every button with delay
+func NewConfig(delay time.Duration) Config {
+	cfg := Config{
+		Delay:             delay,
+		Buttons:           make(map[Button]ButtonConfig),
+		Wheel:             WheelConfig{Window: 100 * time.Millisecond},
+		DoubleClickWindow: DefaultDoubleClickWindow,
+	}
+	for _, b := range Buttons() {
+		cfg.Buttons[b] = ButtonConfig{Enabled: true}
+	}
+	return cfg
+}
+
+// Enabled reports whether a button is filtered
+func (c Config) Enabled(button Button) bool {
+	bc, ok := c.Buttons[button]
+	return !ok || bc.Enabled
+}
+
+// DelayFor returns the user-selected delay of a button
+func (c Config) DelayFor(button Button) time.Duration {
+	if bc, ok := c.Buttons[button]; ok && bc.Delay > 0 {
+		return bc.Delay
+	}
+	return c.Delay
+}
+
+// ForDevice returns the settings the device with the given ID is filtered
+// with
+func (c Config) ForDevice(id string) Config {
+	dc, ok := c.Devices[id]
+	if !ok {
+		return c
+	}
+
+	buttons := make(map[Button]ButtonConfig, len(c.Buttons))
+	for _, b := range Buttons() {
+		bc := ButtonConfig{Enabled: c.Enabled(b), Delay: c.Buttons[b].Delay}
+		if !dc.Enabled {
+			bc.Enabled = false
+		}
+		if dc.Delay > 0 {
+			bc.Delay = dc.Delay
+		}
+		buttons[b] = bc
+	}
+	c.Buttons = buttons
+	if !dc.Enabled {
+		c.Wheel.Enabled = false
+	}
+	return c
+}
@@ -0,0 +1,191 @@
+package filter
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	historySize       = 64                     // Samples kept per button
+	maxTrackedGap     = 2 * time.Second        // Longer gaps are clamped so idle time doesn't skew the split
+	maxBounceInterval = 100 * time.Millisecond // Switch chatter is never slower than this
+	minBounceSamples  = 3                      // Bounces needed before the delay is raised
+	minClusterRatio   = 3.0                    // Intentional clicks must be this many times slower than bounces
+	maxAdaptiveDelay  = 200 * time.Millisecond
+	decayHalfLife     = 5 * time.Minute      // Time for the extra delay to halve without new bounces
+	minDelayChange    = 5 * time.Millisecond // Smaller adjustments are not reported
+	shortHold         = 30 * time.Millisecond
+)
+
+// ring is a fixed-size buffer of the most recent durations
+type ring struct {
+	values []time.Duration
+	next   int
+}
+
+func (r *ring) push(v time.Duration) {
+	if len(r.values) < historySize {
+		r.values = append(r.values, v)
+		return
+	}
+	r.values[r.next] = v
+	r.next = (r.next + 1) % historySize
+}
+
+// buttonHistory holds the recent timings of one button
+type buttonHistory struct {
+	intervals ring // DOWN to DOWN gaps, blocked presses included
+	holds     ring // Press to release durations of allowed clicks
+
+	peak     time.Duration // Adaptive delay at the time of the last bounce
+	evidence time.Time     // When a bounce was last seen
+}
+
+// detector analyzes click timings to detect faulty mouse behavior. Bounces
+// show up as a second, much faster cluster in the inter-press intervals;
+// when one is found the delay is raised just above it, and the extra delay
+// decays back to the user setting once the bounces stop.
+type detector struct {
+	history       map[Button]*buttonHistory
+	adaptiveDelay map[Button]time.Duration // Per-button adaptive delay
+}
+
+func newDetector() *detector {
+	return &detector{
+		history:       make(map[Button]*buttonHistory),
+		adaptiveDelay: make(map[Button]time.Duration),
+	}
+}
+
+func (d *detector) historyFor(button Button) *buttonHistory {
+	h, ok := d.history[button]
+	if !ok {
+		h = &buttonHistory{}
+		d.history[button] = h
+	}
+	return h
+}
+
+// observeDown records the gap between two presses and reports whether the
+// adaptive delay changed
+func (d *detector) observeDown(button Button, interval time.Duration, now time.Time, delay time.Duration) bool {
+	h := d.historyFor(button)
+	h.intervals.push(min(interval, maxTrackedGap))
+
+	// Only a fresh bounce raises the delay, old ones in the history just
+	// keep the clusters apart
+	if bounceMax, intentionalMin, ok := splitBounces(h.intervals.values); ok && interval <= bounceMax {
+		recommended := min(bounceMax*5/4, intentionalMin*4/5, maxAdaptiveDelay)
+		h.peak = max(recommended, d.target(h, now, delay))
+		h.evidence = now
+	}
+
+	return d.update(button, h, now, delay)
+}
+
+// observeUp records the hold time of an allowed click. It returns the number
+// of short holds in the history when this one was short, and whether the
+// adaptive delay changed.
+func (d *detector) observeUp(button Button, hold time.Duration, now time.Time, delay time.Duration) (int, bool) {
+	h := d.historyFor(button)
+	h.holds.push(hold)
+
+	shortClicks := 0
+	if hold < shortHold {
+		for _, v := range h.holds.values {
+			if v < shortHold {
+				shortClicks++
+			}
+		}
+	}
+	return shortClicks, d.update(button, h, now, delay)
+}
+
+// target returns the adaptive delay after decaying the last peak
+func (d *detector) target(h *buttonHistory, now time.Time, delay time.Duration) time.Duration {
+	if h.peak <= delay || h.evidence.IsZero() {
+		return delay
+	}
+	halvings := float64(now.Sub(h.evidence)) / float64(decayHalfLife)
+	extra := time.Duration(float64(h.peak-delay) * math.Pow(0.5, halvings))
+	if extra < time.Millisecond {
+		return delay
+	}
+	return delay + extra
+}
+
+// update moves the adaptive delay towards the decayed target and reports
+// whether it changed noticeably
+func (d *detector) update(button Button, h *buttonHistory, now time.Time, delay time.Duration) bool {
+	target := d.target(h, now, delay)
+	current, ok := d.adaptiveDelay[button]
+	if !ok || current < delay {
+		current = delay
+	}
+
+	change := target - current
+	if change < 0 {
+		change = -change
+	}
+	if change < minDelayChange && (target != delay || current == delay) {
+		return false
+	}
+
+	if target == delay {
+		h.peak = 0
+		delete(d.adaptiveDelay, button)
+	} else {
+		d.adaptiveDelay[button] = target
+	}
+	return true
+}
+
+// splitBounces looks for two well separated clusters in the intervals using
+// Otsu's method on log-scaled values. It returns the slowest bounce and the
+// fastest intentional interval when the faster cluster looks like chatter.
+func splitBounces(intervals []time.Duration) (time.Duration, time.Duration, bool) {
+	if len(intervals) < 2*minBounceSamples {
+		return 0, 0, false
+	}
+
+	sorted := append([]time.Duration(nil), intervals...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	logs := make([]float64, len(sorted))
+	total := 0.0
+	for i, v := range sorted {
+		logs[i] = math.Log(float64(max(v, time.Millisecond)) / float64(time.Millisecond))
+		total += logs[i]
+	}
+
+	n := float64(len(logs))
+	bestSplit, bestVariance := 0, 0.0
+	lowSum := 0.0
+	for k := 1; k < len(logs); k++ {
+		lowSum += logs[k-1]
+		w0 := float64(k) / n
+		m0 := lowSum / float64(k)
+		m1 := (total - lowSum) / (n - float64(k))
+		if variance := w0 * (1 - w0) * (m1 - m0) * (m1 - m0); variance > bestVariance {
+			bestSplit, bestVariance = k, variance
+		}
+	}
+
+	if bestSplit < minBounceSamples {
+		return 0, 0, false
+	}
+	bounceMax, intentionalMin := sorted[bestSplit-1], sorted[bestSplit]
+	if bounceMax > maxBounceInterval || mean(logs[bestSplit:])-mean(logs[:bestSplit]) < math.Log(minClusterRatio) {
+		return 0, 0, false
+	}
+	return bounceMax, intentionalMin, true
+}
+
+func mean(values []float64) float64 {
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
@@ -0,0 +1,178 @@
+package filter
+
+import (
+	"testing"
+	"time"
+)
+
+// durations converts recorded intervals in milliseconds
+func durations(ms ...int) []time.Duration {
+	out := make([]time.Duration, len(ms))
+	for i, v := range ms {
+		out[i] = time.Duration(v) * time.Millisecond
+	}
+	return out
+}
+
+func TestSplitBounces(t *testing.T) {
+	tests := []struct {
+		name           string
+		intervals      []time.Duration
+		ok             bool
+		bounceMax      time.Duration
+		intentionalMin time.Duration
+	}{
+		{
+			// Chattering switch: re-closes 25-35ms after the press
+			name:           "bimodal",
+			intervals:      durations(412, 31, 388, 28, 455, 35, 1200, 26, 390, 33),
+			ok:             true,
+			bounceMax:      35 * time.Millisecond,
+			intentionalMin: 388 * time.Millisecond,
+		},
+		{
+			name:      "unimodal intentional clicks",
+			intervals: durations(310, 280, 450, 390, 520, 300, 610, 340),
+		},
+		{
+			// Fast clicking without a second, much slower cluster
+			name:      "unimodal fast clicks",
+			intervals: durations(95, 110, 102, 98, 120, 105, 99, 115),
+		},
+		{
+			name:      "too few bounces",
+			intervals: durations(400, 30, 380, 32, 450, 390, 410),
+		},
+		{
+			// Two clusters, but the fast one is too slow for chatter
+			name:      "slow fast cluster",
+			intervals: durations(150, 900, 160, 1100, 140, 950, 155, 1000),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bounceMax, intentionalMin, ok := splitBounces(tt.intervals)
+			if ok != tt.ok {
+				t.Fatalf("splitBounces ok = %v, want %v (split %v / %v)", ok, tt.ok, bounceMax, intentionalMin)
+			}
+			if ok && (bounceMax != tt.bounceMax || intentionalMin != tt.intentionalMin) {
+				t.Errorf("split = %v / %v, want %v / %v", bounceMax, intentionalMin, tt.bounceMax, tt.intentionalMin)
+			}
+		})
+	}
+}
+
+// observeIntervals feeds press intervals to d and returns the time of the
+// last press
+func observeIntervals(d *detector, delay time.Duration, intervals []time.Duration) time.Time {
+	now := start
+	for _, interval := range intervals {
+		now = now.Add(interval)
+		d.observeDown(ButtonLeft, interval, now, delay)
+	}
+	return now
+}
+
+func TestDetectorRaisesDelay(t *testing.T) {
+	delay := 20 * time.Millisecond
+	d := newDetector()
+	observeIntervals(d, delay, durations(412, 31, 388, 28, 455, 35, 1200, 26, 390, 33))
+
+	// Just above the slowest bounce, well below the intentional clicks
+	want := 35 * time.Millisecond * 5 / 4
+	if got := d.adaptiveDelay[ButtonLeft]; got != want {
+		t.Errorf("adaptive delay = %v, want %v", got, want)
+	}
+}
+
+func TestDetectorKeepsDelayWithoutBounces(t *testing.T) {
+	delay := 50 * time.Millisecond
+	d := newDetector()
+	observeIntervals(d, delay, durations(310, 280, 450, 390, 520, 300, 610, 340, 95, 110, 102))
+
+	if got, ok := d.adaptiveDelay[ButtonLeft]; ok {
+		t.Errorf("adaptive delay = %v, want none", got)
+	}
+}
+
+func TestDetectorDecay(t *testing.T) {
+	delay := 20 * time.Millisecond
+	d := newDetector()
+	last := observeIntervals(d, delay, durations(412, 31, 388, 28, 455, 35, 1200, 26, 390, 33))
+	h := d.history[ButtonLeft]
+	extra := h.peak - delay
+
+	for _, tt := range []struct {
+		after time.Duration
+		want  time.Duration
+	}{
+		{0, delay + extra},
+		{decayHalfLife, delay + extra/2},
+		{2 * decayHalfLife, delay + extra/4},
+		{10 * decayHalfLife, delay}, // Less than a millisecond left
+	} {
+		if got := d.target(h, last.Add(tt.after), delay); got != tt.want {
+			t.Errorf("target %v after the last bounce = %v, want %v", tt.after, got, tt.want)
+		}
+	}
+
+	// An hour of intentional clicks brings the delay back to the setting
+	now := last
+	for i := 0; i < 60; i++ {
+		now = now.Add(time.Minute)
+		d.observeDown(ButtonLeft, time.Minute, now, delay)
+	}
+	if got, ok := d.adaptiveDelay[ButtonLeft]; ok {
+		t.Errorf("adaptive delay = %v an hour after the last bounce, want none", got)
+	}
+}
+
+func TestEngineReleasesPressThatRaisedDelay(t *testing.T) {
+	// A bounce press 35ms after each release, recorded with a 20ms delay
+	var steps []step
+	for click := 0; click < 6; click++ {
+		t0 := click * 500
+		steps = append(steps,
+			down(t0, ButtonLeft), up(t0+60, ButtonLeft),
+			down(t0+95, ButtonLeft), up(t0+100, ButtonLeft))
+	}
+
+	e := NewEngine(testConfig(func(c *Config) {
+		c.Delay = 20 * time.Millisecond
+	}))
+	adjusted := false
+	pressed := false
+	for i, s := range steps {
+		d := e.Process(s.event())
+		adjusted = adjusted || d.Adjusted
+		switch s.kind {
+		case KindDown:
+			pressed = d.Allow
+		case KindUp:
+			if pressed && !d.Allow {
+				t.Fatalf("step %d: release of an allowed press blocked (%s), the button would stay down", i, d.Reason)
+			}
+			pressed = false
+		}
+	}
+
+	if !adjusted {
+		t.Fatal("the bounces never raised the delay")
+	}
+	if got := e.EffectiveDelay(ButtonLeft); got <= 95*time.Millisecond {
+		t.Errorf("effective delay = %v, want above the 95ms bounces", got)
+	}
+	// The raised delay now blocks the bounce of the next click and its release
+	for _, s := range []step{down(3000, ButtonLeft), up(3060, ButtonLeft)} {
+		if d := e.Process(s.event()); !d.Allow {
+			t.Fatalf("click at %dms blocked (%s)", s.ms, d.Reason)
+		}
+	}
+	if d := e.Process(down(3095, ButtonLeft).event()); d.Allow {
+		t.Error("bounce after the delay was raised was allowed")
+	}
+	if d := e.Process(up(3100, ButtonLeft).event()); d.Allow {
+		t.Error("release of a blocked bounce was allowed")
+	}
+}
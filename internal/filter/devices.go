@@ -0,0 +1,58 @@
+package filter
+
+import "time"
+
+// EngineSet keeps one Engine per attached device so every device has its
+// own timers and adaptive delays, identical models included. Events are
+// routed by Event.DeviceKey, and a device is filtered with the settings of
+// its Event.DeviceID. Events of an unknown device share one engine.
+type EngineSet struct {
+	config  Config
+	engines map[string]*deviceEngine
+}
+
+// deviceEngine is the engine of one device and the ID its settings are
+// looked up by
+type deviceEngine struct {
+	id     string
+	engine *Engine
+}
+
+// NewEngineSet creates an empty set filtering with cfg
+func NewEngineSet(cfg Config) *EngineSet {
+	return &EngineSet{config: cfg, engines: make(map[string]*deviceEngine)}
+}
+
+// Process runs an event through the engine of its device, creating the
+// engine on the first event of the device
+func (s *EngineSet) Process(ev Event) Decision {
+	e, ok := s.engines[ev.DeviceKey]
+	if !ok {
+		e = &deviceEngine{id: ev.DeviceID, engine: NewEngine(s.config.ForDevice(ev.DeviceID))}
+		s.engines[ev.DeviceKey] = e
+	}
+	return e.engine.Process(ev)
+}
+
+// SetConfig switches the settings of every engine
+func (s *EngineSet) SetConfig(cfg Config) {
+	s.config = cfg
+	for _, e := range s.engines {
+		e.engine.SetConfig(cfg.ForDevice(e.id))
+	}
+}
+
+// EffectiveDelay returns the highest delay any enabled device applies to a
+// button, 0 when the button is not filtered
+func (s *EngineSet) EffectiveDelay(button Button) time.Duration {
+	if len(s.engines) == 0 && s.config.Enabled(button) {
+		return s.config.DelayFor(button)
+	}
+	var delay time.Duration
+	for _, e := range s.engines {
+		if s.config.ForDevice(e.id).Enabled(button) {
+			delay = max(delay, e.engine.EffectiveDelay(button))
+		}
+	}
+	return delay
+}
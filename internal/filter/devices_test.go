@@ -0,0 +1,60 @@
+package filter
+
+import (
+	"testing"
+	"time"
+)
+
+// on returns the event of s as produced by the device with key and id
+func on(s step, key, id string) Event {
+	ev := s.event()
+	ev.DeviceKey, ev.DeviceID = key, id
+	return ev
+}
+
+func TestEngineSetSeparatesDevices(t *testing.T) {
+	cfg := testConfig(func(c *Config) {
+		c.Devices = map[string]DeviceConfig{
+			"046d:c077": {Enabled: true, Delay: 80 * time.Millisecond},
+			"1234:5678": {Enabled: false},
+		}
+	})
+	s := NewEngineSet(cfg)
+
+	tests := []struct {
+		name  string
+		event Event
+		want  Reason
+	}{
+		// Two identical mice clicking in turn do not block each other
+		{"first mouse press", on(down(0, ButtonLeft), "/dev/input/event3", "046d:c077"), ReasonNone},
+		{"first mouse release", on(up(10, ButtonLeft), "/dev/input/event3", "046d:c077"), ReasonNone},
+		{"second mouse press", on(down(20, ButtonLeft), "/dev/input/event4", "046d:c077"), ReasonNone},
+		{"second mouse release", on(up(30, ButtonLeft), "/dev/input/event4", "046d:c077"), ReasonNone},
+		// Both use the 80ms delay saved for their ID
+		{"first mouse bounce", on(down(70, ButtonLeft), "/dev/input/event3", "046d:c077"), ReasonRapidDown},
+		{"second mouse bounce", on(down(90, ButtonLeft), "/dev/input/event4", "046d:c077"), ReasonRapidDown},
+		// A disabled device passes, an unknown one uses the global settings
+		{"disabled device press", on(down(0, ButtonLeft), "/dev/input/event5", "1234:5678"), ReasonNone},
+		{"disabled device bounce", on(down(5, ButtonLeft), "/dev/input/event5", "1234:5678"), ReasonNone},
+		{"unknown device press", down(0, ButtonLeft).event(), ReasonNone},
+		{"unknown device release", up(10, ButtonLeft).event(), ReasonNone},
+		{"unknown device bounce", down(40, ButtonLeft).event(), ReasonRapidDown},
+	}
+	for _, tt := range tests {
+		if d := s.Process(tt.event); d.Reason != tt.want {
+			t.Errorf("%s: reason %q, want %q", tt.name, d.Reason, tt.want)
+		}
+	}
+
+	if got := s.EffectiveDelay(ButtonLeft); got != 80*time.Millisecond {
+		t.Errorf("effective delay = %v, want the 80ms of the slowest device", got)
+	}
+
+	// New settings reach the engines already created
+	cfg.Devices = nil
+	s.SetConfig(cfg)
+	if got := s.EffectiveDelay(ButtonLeft); got != 50*time.Millisecond {
+		t.Errorf("effective delay after SetConfig = %v, want 50ms", got)
+	}
+}
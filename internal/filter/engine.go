@@ -0,0 +1,206 @@
+package filter
+
+import (
+	"time"
+)
+
+// minDoubleClickGap is the shortest release-to-press gap of an intentional
+// double-click; switch chatter re-closes the contact sooner than this
+const minDoubleClickGap = 40 * time.Millisecond
+
+// Engine decides which mouse events pass through. It holds no platform
+// state and is not safe for concurrent use; callers serialize access.
+type Engine struct {
+	config            Config
+	lastCompleteClick time.Time
+	lastClickButton   Button
+	buttonPressed     map[Button]bool
+	buttonPressTime   map[Button]time.Time
+	dragDetected      map[Button]bool
+
+	// Track last DOWN and UP event times
+	lastDownTime    map[Button]time.Time
+	lastDownBlocked map[Button]bool
+	lastUpTime      map[Button]time.Time
+	lastHold        map[Button]time.Duration // Hold time of the last allowed click
+	pressDelay      map[Button]time.Duration // Delay in effect when the current press was allowed
+
+	detector *detector
+	wheel    wheelState
+}
+
+// NewEngine creates an engine that filters with cfg
+func NewEngine(cfg Config) *Engine {
+	return &Engine{
+		config:          cfg,
+		buttonPressed:   make(map[Button]bool),
+		buttonPressTime: make(map[Button]time.Time),
+		dragDetected:    make(map[Button]bool),
+		lastDownTime:    make(map[Button]time.Time),
+		lastDownBlocked: make(map[Button]bool),
+		lastUpTime:      make(map[Button]time.Time),
+		lastHold:        make(map[Button]time.Duration),
+		pressDelay:      make(map[Button]time.Duration),
+		detector:        newDetector(),
+	}
+}
+
+// SetConfig replaces the settings; the timing state of the buttons is kept
+// so a press right after the switch is still checked against the last one
+func (e *Engine) SetConfig(cfg Config) {
+	e.config = cfg
+}
+
+// Process evaluates an event and returns whether it should be allowed
+func (e *Engine) Process(ev Event) Decision {
+	if ev.Kind == KindWheel {
+		return e.wheel.process(e.config.Wheel, ev.Delta, ev.Time)
+	}
+	if ev.Kind != KindMove && !e.config.Enabled(ev.Button) {
+		return Decision{Allow: true}
+	}
+
+	switch ev.Kind {
+	case KindDown:
+		return e.processDown(ev.Button, ev.Time)
+	case KindUp:
+		return e.processUp(ev.Button, ev.Time)
+	case KindMove:
+		return e.processMove()
+	}
+	return Decision{Allow: true}
+}
+
+// EffectiveDelay returns the adaptive delay for a specific button.
+// Never returns a delay less than the user-selected delay of the button.
+func (e *Engine) EffectiveDelay(button Button) time.Duration {
+	delay := e.config.DelayFor(button)
+	if adaptiveDelay, exists := e.detector.adaptiveDelay[button]; exists && adaptiveDelay >= delay {
+		return adaptiveDelay
+	}
+	return delay
+}
+
+func (e *Engine) processDown(button Button, now time.Time) Decision {
+	lastDown := e.lastDownTime[button]
+	d := e.decideDown(button, now)
+
+	// Every press, blocked or not, feeds the faulty hardware detection
+	if !lastDown.IsZero() {
+		d.Since = now.Sub(lastDown)
+		if e.detector.observeDown(button, d.Since, now, e.config.DelayFor(button)) {
+			d.Adjusted = true
+			d.AdaptiveDelay = e.EffectiveDelay(button)
+		}
+	}
+	return d
+}
+
+func (e *Engine) decideDown(button Button, now time.Time) Decision {
+	delay := e.EffectiveDelay(button)
+	doubleClick := e.config.Strategy == StrategyDoubleClick && e.isDoubleClick(button, now)
+
+	lastDown := e.lastDownTime[button]
+	interval := now.Sub(lastDown)
+	e.lastDownTime[button] = now
+
+	// Strictly block any DOWN events within the delay while pressed; checked
+	// first since the previous DOWN is never older than the press
+	if e.buttonPressed[button] {
+		timeSincePress := now.Sub(e.buttonPressTime[button])
+		if timeSincePress < delay {
+			e.lastDownBlocked[button] = true
+			return Decision{Reason: ReasonBounce, Interval: timeSincePress, Delay: delay}
+		}
+	}
+
+	if !lastDown.IsZero() && interval < delay && !doubleClick {
+		// Always block rapid successive DOWN events (hardware bounce or double-click)
+		e.lastDownBlocked[button] = true
+		return Decision{Reason: ReasonRapidDown, Interval: interval, Delay: delay}
+	}
+	e.lastDownBlocked[button] = false
+
+	// Strictly block rapid successive complete clicks
+	if !e.lastCompleteClick.IsZero() && button == e.lastClickButton {
+		sinceClick := now.Sub(e.lastCompleteClick)
+		if sinceClick < delay && !doubleClick {
+			return Decision{Reason: ReasonRapidClick, Interval: sinceClick, Delay: delay}
+		}
+	}
+
+	// Allow the click and mark button as pressed
+	e.buttonPressed[button] = true
+	e.buttonPressTime[button] = now
+	e.dragDetected[button] = false
+	d := Decision{Allow: true, Delay: delay}
+	if doubleClick && (interval < delay || now.Sub(e.lastCompleteClick) < delay) {
+		d.DoubleClick = true
+		d.Interval = now.Sub(e.lastUpTime[button])
+	}
+	e.pressDelay[button] = delay
+	return d
+}
+
+// isDoubleClick reports whether a press looks like the second click of an
+// intentional double-click: the previous allowed click of the button was
+// held like a human click, and the button was released for longer than
+// chatter lasts but within the double-click window
+func (e *Engine) isDoubleClick(button Button, now time.Time) bool {
+	if e.buttonPressed[button] || e.lastClickButton != button {
+		return false
+	}
+	lastUp := e.lastUpTime[button]
+	if lastUp.IsZero() || e.lastHold[button] < shortHold {
+		return false
+	}
+	gap := now.Sub(lastUp)
+	return gap >= minDoubleClickGap && gap < e.config.DoubleClickWindow
+}
+
+func (e *Engine) processUp(button Button, now time.Time) Decision {
+	// Block UP events without a press. The release of an allowed press
+	// always passes, even when that press raised the adaptive delay, or the
+	// button would stay held down.
+	if !e.buttonPressed[button] {
+		return Decision{Reason: ReasonSpuriousUp, Interval: now.Sub(e.lastUpTime[button]), Delay: e.EffectiveDelay(button)}
+	}
+	delay := e.pressDelay[button]
+	e.lastUpTime[button] = now
+
+	holdDuration := now.Sub(e.buttonPressTime[button])
+	e.lastHold[button] = holdDuration
+	e.lastCompleteClick = now
+	e.lastClickButton = button
+	e.buttonPressed[button] = false
+
+	d := Decision{
+		Allow:    true,
+		Interval: holdDuration,
+		Delay:    delay,
+		Hold:     holdDuration,
+		Drag:     e.dragDetected[button],
+	}
+
+	// Analyze click pattern for faulty hardware detection
+	d.ShortClicks, d.Adjusted = e.detector.observeUp(button, holdDuration, now, e.config.DelayFor(button))
+	if d.Adjusted {
+		d.AdaptiveDelay = e.EffectiveDelay(button)
+	}
+
+	// Reset drag detection
+	e.dragDetected[button] = false
+	return d
+}
+
+func (e *Engine) processMove() Decision {
+	d := Decision{Allow: true}
+	// Mark pressed buttons as dragging, reporting each drag only once
+	for _, button := range Buttons() {
+		if e.buttonPressed[button] && !e.dragDetected[button] {
+			e.dragDetected[button] = true
+			d.DragStarted = append(d.DragStarted, button)
+		}
+	}
+	return d
+}
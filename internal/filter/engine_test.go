@@ -0,0 +1,196 @@
+package filter
+
+import (
+	"testing"
+	"time"
+)
+
+// start is the time recorded sequences are replayed from
+var start = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// step is one event of a recorded sequence, at ms milliseconds after start
+type step struct {
+	ms     int
+	kind   Kind
+	button Button
+	delta  int
+}
+
+func down(ms int, b Button) step { return step{ms: ms, kind: KindDown, button: b} }
+func up(ms int, b Button) step   { return step{ms: ms, kind: KindUp, button: b} }
+func wheel(ms, delta int) step   { return step{ms: ms, kind: KindWheel, delta: delta} }
+
+func (s step) event() Event {
+	return Event{
+		Kind:   s.kind,
+		Button: s.button,
+		Delta:  s.delta,
+		Time:   start.Add(time.Duration(s.ms) * time.Millisecond),
+	}
+}
+
+// testConfig returns a 50ms configuration changed by fn
+func testConfig(fn func(*Config)) Config {
+	cfg := NewConfig(50 * time.Millisecond)
+	if fn != nil {
+		fn(&cfg)
+	}
+	return cfg
+}
+
+func TestEngineReasons(t *testing.T) {
+	const none = ReasonNone
+
+	tests := []struct {
+		name   string
+		config Config
+		steps  []step
+		want   []Reason // ReasonNone for allowed events
+	}{
+		{
+			name:   "slow clicks pass",
+			config: testConfig(nil),
+			steps:  []step{down(0, ButtonLeft), up(80, ButtonLeft), down(200, ButtonLeft), up(260, ButtonLeft)},
+			want:   []Reason{none, none, none, none},
+		},
+		{
+			name:   "rapid down after release",
+			config: testConfig(nil),
+			steps:  []step{down(0, ButtonLeft), up(10, ButtonLeft), down(30, ButtonLeft), up(35, ButtonLeft)},
+			want:   []Reason{none, none, ReasonRapidDown, ReasonSpuriousUp},
+		},
+		{
+			name:   "bounce while pressed",
+			config: testConfig(nil),
+			steps:  []step{down(0, ButtonLeft), down(10, ButtonLeft), up(100, ButtonLeft)},
+			want:   []Reason{none, ReasonBounce, none},
+		},
+		{
+			name:   "rapid complete click",
+			config: testConfig(nil),
+			steps:  []step{down(0, ButtonLeft), up(100, ButtonLeft), down(120, ButtonLeft), up(130, ButtonLeft)},
+			want:   []Reason{none, none, ReasonRapidClick, ReasonSpuriousUp},
+		},
+		{
+			name:   "up without press",
+			config: testConfig(nil),
+			steps:  []step{up(0, ButtonRight)},
+			want:   []Reason{ReasonSpuriousUp},
+		},
+		{
+			name:   "other button is independent",
+			config: testConfig(nil),
+			steps:  []step{down(0, ButtonLeft), up(10, ButtonLeft), down(20, ButtonRight), up(30, ButtonRight)},
+			want:   []Reason{none, none, none, none},
+		},
+		{
+			name: "disabled button passes",
+			config: testConfig(func(c *Config) {
+				c.Buttons[ButtonRight] = ButtonConfig{Enabled: false}
+			}),
+			steps: []step{down(0, ButtonRight), up(5, ButtonRight), down(10, ButtonRight), up(15, ButtonRight), up(20, ButtonRight)},
+			want:  []Reason{none, none, none, none, none},
+		},
+		{
+			name: "shorter delay of a button",
+			config: testConfig(func(c *Config) {
+				c.Buttons[ButtonLeft] = ButtonConfig{Enabled: true, Delay: 20 * time.Millisecond}
+			}),
+			steps: []step{down(0, ButtonLeft), up(10, ButtonLeft), down(40, ButtonLeft), up(50, ButtonLeft)},
+			want:  []Reason{none, none, none, none},
+		},
+		{
+			name: "default delay of the other buttons",
+			config: testConfig(func(c *Config) {
+				c.Buttons[ButtonLeft] = ButtonConfig{Enabled: true, Delay: 20 * time.Millisecond}
+			}),
+			steps: []step{down(0, ButtonRight), up(10, ButtonRight), down(40, ButtonRight)},
+			want:  []Reason{none, none, ReasonRapidDown},
+		},
+		{
+			name: "longer delay of a button",
+			config: testConfig(func(c *Config) {
+				c.Buttons[ButtonMiddle] = ButtonConfig{Enabled: true, Delay: 100 * time.Millisecond}
+			}),
+			steps: []step{down(0, ButtonMiddle), up(30, ButtonMiddle), down(80, ButtonMiddle)},
+			want:  []Reason{none, none, ReasonRapidDown},
+		},
+		{
+			name: "intentional double-click",
+			config: testConfig(func(c *Config) {
+				c.Delay = 300 * time.Millisecond
+				c.Strategy = StrategyDoubleClick
+			}),
+			steps: []step{down(0, ButtonLeft), up(80, ButtonLeft), down(200, ButtonLeft), up(260, ButtonLeft)},
+			want:  []Reason{none, none, none, none},
+		},
+		{
+			name: "strict double-click",
+			config: testConfig(func(c *Config) {
+				c.Delay = 300 * time.Millisecond
+			}),
+			steps: []step{down(0, ButtonLeft), up(80, ButtonLeft), down(200, ButtonLeft)},
+			want:  []Reason{none, none, ReasonRapidDown},
+		},
+		{
+			name: "wheel reversal",
+			config: testConfig(func(c *Config) {
+				c.Wheel.Enabled = true
+			}),
+			steps: []step{wheel(0, 1), wheel(30, 1), wheel(60, 1), wheel(90, -1), wheel(120, 1)},
+			want:  []Reason{none, none, none, ReasonWheelReversal, none},
+		},
+		{
+			name: "wheel direction change",
+			config: testConfig(func(c *Config) {
+				c.Wheel.Enabled = true
+			}),
+			steps: []step{wheel(0, 1), wheel(30, 1), wheel(60, 1), wheel(90, -1), wheel(120, -1)},
+			want:  []Reason{none, none, none, ReasonWheelReversal, none},
+		},
+		{
+			name:   "wheel filter disabled",
+			config: testConfig(nil),
+			steps:  []step{wheel(0, 1), wheel(30, 1), wheel(60, 1), wheel(90, -1)},
+			want:   []Reason{none, none, none, none},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewEngine(tt.config)
+			for i, s := range tt.steps {
+				d := e.Process(s.event())
+				want := tt.want[i]
+				if d.Allow != (want == ReasonNone) || d.Reason != want {
+					t.Errorf("step %d (%s %s at %dms): got allow=%v reason=%q, want reason %q",
+						i, s.button, s.kind, s.ms, d.Allow, d.Reason, want)
+				}
+			}
+		})
+	}
+}
+
+func TestEngineDelayOverrides(t *testing.T) {
+	cfg := testConfig(func(c *Config) {
+		c.Buttons[ButtonLeft] = ButtonConfig{Enabled: true, Delay: 20 * time.Millisecond}
+		c.Buttons[ButtonX1] = ButtonConfig{Enabled: true, Delay: 120 * time.Millisecond}
+	})
+	e := NewEngine(cfg)
+
+	want := map[Button]time.Duration{
+		ButtonLeft:   20 * time.Millisecond,
+		ButtonRight:  50 * time.Millisecond,
+		ButtonMiddle: 50 * time.Millisecond,
+		ButtonX1:     120 * time.Millisecond,
+		ButtonX2:     50 * time.Millisecond,
+	}
+	for button, delay := range want {
+		if got := e.EffectiveDelay(button); got != delay {
+			t.Errorf("EffectiveDelay(%s) = %v, want %v", button, got, delay)
+		}
+		if d := e.Process(down(0, button).event()); !d.Allow || d.Delay != delay {
+			t.Errorf("%s press: got allow=%v delay=%v, want allowed with %v", button, d.Allow, d.Delay, delay)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package filter
+
+import (
+	"strings"
+	"time"
+)
+
+// Button identifies a mouse button independently of the platform
+type Button int
+
+const (
+	ButtonLeft Button = iota
+	ButtonRight
+	ButtonMiddle
+	ButtonX1 // Side button, usually "back"
+	ButtonX2 // Side button, usually "forward"
+)
+
+// Buttons returns every button the engine filters
+func Buttons() []Button {
+	return []Button{ButtonLeft, ButtonRight, ButtonMiddle, ButtonX1, ButtonX2}
+}
+
+// ParseButton looks up a button by its name, ignoring case
+func ParseButton(name string) (Button, bool) {
+	for _, b := range Buttons() {
+		if strings.EqualFold(b.String(), name) {
+			return b, true
+		}
+	}
+	return 0, false
+}
+
+// String returns the display name of the button
+func (b Button) String() string {
+	switch b {
+	case ButtonLeft:
+		return "Left"
+	case ButtonRight:
+		return "Right"
+	case ButtonMiddle:
+		return "Middle"
+	case ButtonX1:
+		return "X1"
+	case ButtonX2:
+		return "X2"
+	default:
+		return "Unknown"
+	}
+}
+
+// Kind describes what happened in a mouse event
+type Kind int
+
+const (
+	KindDown Kind = iota
+	KindUp
+	KindMove
+	KindWheel
+)
+
+// String returns the lowercase name of the kind
+func (k Kind) String() string {
+	switch k {
+	case KindDown:
+		return "down"
+	case KindUp:
+		return "up"
+	case KindMove:
+		return "move"
+	case KindWheel:
+		return "wheel"
+	default:
+		return "unknown"
+	}
+}
+
+// Event is a platform-independent mouse event fed into the Engine
+type Event struct {
+	Kind   Kind
+	Button Button // Ignored for KindMove and KindWheel
+	Delta  int    // Wheel rotation for KindWheel, positive away from the user
+	Time   time.Time
+
+	// DeviceKey tells the attached device that produced the event apart
+	// from identical ones, DeviceID selects its settings (see
+	// Config.ForDevice). Both are "" when the device is not known.
+	DeviceKey string
+	DeviceID  string
+}
+
+// Reason explains why an event was blocked
+type Reason int
+
+const (
+	ReasonNone          Reason = iota
+	ReasonRapidDown            // DOWN within the delay of the previous DOWN
+	ReasonBounce               // DOWN while the button is still pressed
+	ReasonRapidClick           // DOWN within the delay of the previous complete click
+	ReasonSpuriousUp           // UP without a matching allowed press
+	ReasonWheelReversal        // Isolated wheel tick against the scroll direction
+)
+
+// BlockReasons returns every reason an event can be blocked for
+func BlockReasons() []Reason {
+	return []Reason{ReasonRapidDown, ReasonBounce, ReasonRapidClick, ReasonSpuriousUp}
+}
+
+// String returns a short description of the reason
+func (r Reason) String() string {
+	switch r {
+	case ReasonNone:
+		return "none"
+	case ReasonRapidDown:
+		return "rapid down"
+	case ReasonBounce:
+		return "bounce while pressed"
+	case ReasonRapidClick:
+		return "rapid complete click"
+	case ReasonSpuriousUp:
+		return "spurious up"
+	case ReasonWheelReversal:
+		return "wheel reversal"
+	default:
+		return "unknown"
+	}
+}
+
+// Decision is the outcome of processing a single Event
+type Decision struct {
+	Allow    bool
+	Reason   Reason
+	Interval time.Duration // Gap that triggered the block (or time since press for allowed UPs)
+	Delay    time.Duration // Effective delay applied to the button, or the wheel window
+
+	// Since is the time since the previous DOWN of the button on a DOWN
+	// event, blocked presses included; zero for the first press
+	Since time.Duration
+
+	// Hold and Drag describe an allowed UP event
+	Hold time.Duration
+	Drag bool
+
+	// DoubleClick is set on an allowed DOWN that the strict strategy would
+	// have blocked but that looked like an intentional double-click
+	DoubleClick bool
+
+	// DragStarted lists buttons that started a drag on a KindMove event
+	DragStarted []Button
+
+	// ShortClicks is the number of very short holds among the recent clicks
+	// when the processed UP was one, zero otherwise
+	ShortClicks int
+
+	// Adjusted is set when the adaptive delay of the button changed;
+	// AdaptiveDelay holds the delay now in effect
+	Adjusted      bool
+	AdaptiveDelay time.Duration
+
+	// Bypassed is set by hooks on events they let through without filtering
+	// while the bypass modifier was held
+	Bypassed bool
+}
+
+// Observer receives every processed event together with its decision.
+// It is called from the hook and must not block.
+type Observer func(Event, Decision)
@@ -0,0 +1,48 @@
+package filter
+
+import "time"
+
+// HistogramBuckets are the upper bounds of the timing histogram buckets
+var HistogramBuckets = [...]time.Duration{
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	20 * time.Millisecond,
+	30 * time.Millisecond,
+	50 * time.Millisecond,
+	75 * time.Millisecond,
+	100 * time.Millisecond,
+	150 * time.Millisecond,
+	200 * time.Millisecond,
+	300 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2 * time.Second,
+}
+
+// Histogram counts durations in HistogramBuckets. It is a plain value and
+// can be copied.
+type Histogram struct {
+	Counts [len(HistogramBuckets) + 1]int `json:"counts"` // Per bucket, the last one counts longer durations
+	Sum    time.Duration                  `json:"sum"`
+	Count  int                            `json:"count"`
+}
+
+// Observe adds a duration to the histogram
+func (h *Histogram) Observe(d time.Duration) {
+	i := 0
+	for i < len(HistogramBuckets) && d > HistogramBuckets[i] {
+		i++
+	}
+	h.Counts[i]++
+	h.Sum += d
+	h.Count++
+}
+
+// Add merges another histogram into h
+func (h *Histogram) Add(other Histogram) {
+	for i, n := range other.Counts {
+		h.Counts[i] += n
+	}
+	h.Sum += other.Sum
+	h.Count += other.Count
+}
@@ -0,0 +1,142 @@
+package filter
+
+import "time"
+
+// DefaultKeyThreshold is short enough for fast typists doubling a letter
+// and longer than the chatter of a worn key switch
+const DefaultKeyThreshold = 40 * time.Millisecond
+
+// KeyConfig holds the keyboard chatter filter settings
+type KeyConfig struct {
+	Threshold time.Duration            // Presses this soon after the release of the key are chatter
+	Keys      map[string]time.Duration // Thresholds per key name overriding Threshold
+}
+
+// ThresholdFor returns the threshold of a key
+func (c KeyConfig) ThresholdFor(name string) time.Duration {
+	if t, ok := c.Keys[name]; ok && t > 0 {
+		return t
+	}
+	return c.Threshold
+}
+
+// KeyEvent is a key transition reported by a keyboard hook
+type KeyEvent struct {
+	Code uint32 // Platform key code
+	Name string // Display name of the key, also used for per-key thresholds
+	Down bool   // Press, or an auto-repeat while held; false for a release
+	Time time.Time
+}
+
+// KeyDecision is the outcome of filtering a KeyEvent
+type KeyDecision struct {
+	Allow     bool
+	Repeat    bool          // An auto-repeat of a held key, blocked when its press was
+	Interval  time.Duration // Time since the previous release of a blocked press
+	Threshold time.Duration // Threshold applied to the key
+}
+
+// keyState tracks a single key
+type keyState struct {
+	held        bool      // Pressed as far as applications know
+	lastRelease time.Time // Last allowed release
+	blocked     bool      // The physical press in progress was blocked, so is its release
+}
+
+// KeyEngine suppresses key chatter: a key pressed again shortly after its
+// previous press was released. Auto-repeat sends further presses while the
+// key is held, without a release in between, and always passes. Like
+// Engine it is not safe for concurrent use.
+type KeyEngine struct {
+	config KeyConfig
+	keys   map[uint32]*keyState
+}
+
+// NewKeyEngine creates an engine that filters with cfg
+func NewKeyEngine(cfg KeyConfig) *KeyEngine {
+	return &KeyEngine{config: cfg, keys: make(map[uint32]*keyState)}
+}
+
+// Process evaluates a key event and returns whether it should be allowed
+func (e *KeyEngine) Process(ev KeyEvent) KeyDecision {
+	st, ok := e.keys[ev.Code]
+	if !ok {
+		st = &keyState{}
+		e.keys[ev.Code] = st
+	}
+	threshold := e.config.ThresholdFor(ev.Name)
+
+	if !ev.Down {
+		if st.blocked {
+			// The release of a blocked press is chatter as well
+			st.blocked = false
+			return KeyDecision{Threshold: threshold}
+		}
+		if st.held {
+			st.held = false
+			st.lastRelease = ev.Time
+		}
+		return KeyDecision{Allow: true, Threshold: threshold}
+	}
+
+	switch {
+	case st.blocked:
+		// Auto-repeat of a blocked press
+		return KeyDecision{Repeat: true, Threshold: threshold}
+	case st.held:
+		return KeyDecision{Allow: true, Repeat: true, Threshold: threshold}
+	}
+
+	interval := ev.Time.Sub(st.lastRelease)
+	if !st.lastRelease.IsZero() && interval < threshold {
+		st.blocked = true
+		return KeyDecision{Interval: interval, Threshold: threshold}
+	}
+	st.held = true
+	return KeyDecision{Allow: true, Threshold: threshold}
+}
+
+// KeyStats counts allowed and blocked key presses
+type KeyStats struct {
+	Allowed int            // Allowed presses, auto-repeats not included
+	Blocked map[string]int // Blocked presses per key name
+}
+
+// NewKeyStats returns empty statistics
+func NewKeyStats() KeyStats {
+	return KeyStats{Blocked: make(map[string]int)}
+}
+
+// Record counts the decision taken for a press; releases are not counted
+func (s *KeyStats) Record(ev KeyEvent, d KeyDecision) {
+	if s.Blocked == nil {
+		*s = NewKeyStats()
+	}
+	if !ev.Down || d.Repeat {
+		return
+	}
+	if d.Allow {
+		s.Allowed++
+	} else {
+		s.Blocked[ev.Name]++
+	}
+}
+
+// TotalBlocked returns the number of blocked presses of every key
+func (s KeyStats) TotalBlocked() int {
+	total := 0
+	for _, n := range s.Blocked {
+		total += n
+	}
+	return total
+}
+
+// Clone returns a deep copy of the statistics
+func (s KeyStats) Clone() KeyStats {
+	c := NewKeyStats()
+	c.Allowed = s.Allowed
+	for name, n := range s.Blocked {
+		c.Blocked[name] = n
+	}
+	return c
+}
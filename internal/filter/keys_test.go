@@ -0,0 +1,69 @@
+package filter
+
+import (
+	"testing"
+	"time"
+)
+
+// key is a transition of the E key at ms milliseconds after start
+func key(ms int, down bool) KeyEvent {
+	return KeyEvent{Code: 18, Name: "E", Down: down, Time: start.Add(time.Duration(ms) * time.Millisecond)}
+}
+
+func TestKeyEngine(t *testing.T) {
+	const (
+		press   = true
+		release = false
+	)
+
+	tests := []struct {
+		name   string
+		events []KeyEvent
+		allow  []bool
+	}{
+		{
+			name:   "doubled letter",
+			events: []KeyEvent{key(0, press), key(70, release), key(120, press), key(190, release)},
+			allow:  []bool{true, true, true, true},
+		},
+		{
+			// Measured from the release, not from the previous press
+			name:   "chatter after a long hold",
+			events: []KeyEvent{key(0, press), key(300, release), key(310, press), key(315, release), key(400, press)},
+			allow:  []bool{true, true, false, false, true},
+		},
+		{
+			name:   "auto-repeat",
+			events: []KeyEvent{key(0, press), key(500, press), key(530, press), key(560, release)},
+			allow:  []bool{true, true, true, true},
+		},
+		{
+			name:   "auto-repeat of a blocked press",
+			events: []KeyEvent{key(0, press), key(60, release), key(80, press), key(580, press), key(600, release)},
+			allow:  []bool{true, true, false, false, false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewKeyEngine(KeyConfig{Threshold: DefaultKeyThreshold})
+			for i, ev := range tt.events {
+				if d := e.Process(ev); d.Allow != tt.allow[i] {
+					t.Errorf("event %d (down=%v at %v): allow = %v, want %v",
+						i, ev.Down, ev.Time.Sub(start), d.Allow, tt.allow[i])
+				}
+			}
+		})
+	}
+}
+
+func TestKeyEngineThresholdOverride(t *testing.T) {
+	e := NewKeyEngine(KeyConfig{Threshold: DefaultKeyThreshold, Keys: map[string]time.Duration{"E": 80 * time.Millisecond}})
+	e.Process(key(0, true))
+	e.Process(key(50, false))
+	d := e.Process(key(110, true))
+	if d.Allow || d.Interval != 60*time.Millisecond || d.Threshold != 80*time.Millisecond {
+		t.Errorf("got allow=%v interval=%v threshold=%v, want blocked 60ms after the release with 80ms",
+			d.Allow, d.Interval, d.Threshold)
+	}
+}
@@ -0,0 +1,133 @@
+package filter
+
+import "time"
+
+// Stats counts allowed clicks and blocked events per button and reason
+type Stats struct {
+	Allowed map[Button]int            // Allowed presses per button
+	Blocked map[Button]map[Reason]int // Blocked events per button and reason
+
+	Intervals map[Button]Histogram // Gaps between presses, blocked ones included
+	Holds     map[Button]Histogram // Hold durations of allowed clicks
+
+	// Delays holds the effective delay per button when the snapshot was
+	// taken; it is filled by the hook and not recorded
+	Delays map[Button]time.Duration
+
+	WheelAllowed int // Allowed wheel ticks
+	WheelBlocked int // Wheel ticks blocked as direction glitches
+}
+
+// NewStats returns empty statistics
+func NewStats() Stats {
+	return Stats{
+		Allowed:   make(map[Button]int),
+		Blocked:   make(map[Button]map[Reason]int),
+		Intervals: make(map[Button]Histogram),
+		Holds:     make(map[Button]Histogram),
+		Delays:    make(map[Button]time.Duration),
+	}
+}
+
+// Record counts the decision taken for an event
+func (s *Stats) Record(ev Event, d Decision) {
+	if s.Allowed == nil || s.Blocked == nil || s.Intervals == nil || s.Holds == nil {
+		*s = NewStats()
+	}
+
+	if ev.Kind == KindDown && d.Since > 0 {
+		h := s.Intervals[ev.Button]
+		h.Observe(d.Since)
+		s.Intervals[ev.Button] = h
+	}
+	if ev.Kind == KindUp && d.Allow {
+		h := s.Holds[ev.Button]
+		h.Observe(d.Hold)
+		s.Holds[ev.Button] = h
+	}
+
+	switch {
+	case ev.Kind == KindMove:
+		return
+	case ev.Kind == KindWheel:
+		if d.Allow {
+			s.WheelAllowed++
+		} else {
+			s.WheelBlocked++
+		}
+	case d.Allow:
+		if ev.Kind == KindDown {
+			s.Allowed[ev.Button]++
+		}
+	default:
+		if s.Blocked[ev.Button] == nil {
+			s.Blocked[ev.Button] = make(map[Reason]int)
+		}
+		s.Blocked[ev.Button][d.Reason]++
+	}
+}
+
+// Clone returns a deep copy that is safe to hand to other goroutines
+func (s Stats) Clone() Stats {
+	c := NewStats()
+	c.WheelAllowed = s.WheelAllowed
+	c.WheelBlocked = s.WheelBlocked
+	for button, n := range s.Allowed {
+		c.Allowed[button] = n
+	}
+	for button, reasons := range s.Blocked {
+		c.Blocked[button] = make(map[Reason]int, len(reasons))
+		for reason, n := range reasons {
+			c.Blocked[button][reason] = n
+		}
+	}
+	for button, h := range s.Intervals {
+		c.Intervals[button] = h
+	}
+	for button, h := range s.Holds {
+		c.Holds[button] = h
+	}
+	for button, delay := range s.Delays {
+		c.Delays[button] = delay
+	}
+	return c
+}
+
+// BlockedClicks returns the number of blocked presses for a button.
+// Spurious releases are not counted since they never produce a click.
+func (s Stats) BlockedClicks(button Button) int {
+	total := 0
+	for reason, n := range s.Blocked[button] {
+		if reason != ReasonSpuriousUp {
+			total += n
+		}
+	}
+	return total
+}
+
+// TotalBlockedClicks returns the number of blocked presses over all buttons
+func (s Stats) TotalBlockedClicks() int {
+	total := 0
+	for button := range s.Blocked {
+		total += s.BlockedClicks(button)
+	}
+	return total
+}
+
+// TotalBlocked returns the number of blocked events for a reason over all buttons
+func (s Stats) TotalBlocked(reason Reason) int {
+	total := 0
+	for _, reasons := range s.Blocked {
+		total += reasons[reason]
+	}
+	return total
+}
+
+// TotalAllowed returns the number of allowed presses over all buttons
+func (s Stats) TotalAllowed() int {
+	total := 0
+	for _, n := range s.Allowed {
+		total += n
+	}
+	return total
+}
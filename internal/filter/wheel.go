@@ -0,0 +1,47 @@
+package filter
+
+import (
+	"time"
+)
+
+// wheelState suppresses isolated direction reversals during continuous
+// scrolling, the typical failure of a worn scroll encoder
+type wheelState struct {
+	lastTick  time.Time
+	direction int  // Sign of the current scroll direction
+	run       int  // Consecutive ticks in the current direction
+	pending   bool // The previous tick was blocked as a reversal
+}
+
+func (w *wheelState) process(cfg WheelConfig, delta int, now time.Time) Decision {
+	if !cfg.Enabled || delta == 0 {
+		return Decision{Allow: true}
+	}
+
+	direction := 1
+	if delta < 0 {
+		direction = -1
+	}
+	gap := now.Sub(w.lastTick)
+	continuous := !w.lastTick.IsZero() && gap < cfg.Window
+	w.lastTick = now
+
+	if direction == w.direction {
+		// Still scrolling the same way, a blocked reversal was a glitch
+		w.run++
+		w.pending = false
+		return Decision{Allow: true, Interval: gap, Delay: cfg.Window}
+	}
+
+	// Block the first tick against a steady scroll; a second one in a row
+	// means the user really changed direction
+	if continuous && w.run >= 2 && !w.pending {
+		w.pending = true
+		return Decision{Reason: ReasonWheelReversal, Interval: gap, Delay: cfg.Window}
+	}
+
+	w.direction = direction
+	w.run = 1
+	w.pending = false
+	return Decision{Allow: true, Interval: gap, Delay: cfg.Window}
+}
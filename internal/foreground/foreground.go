@@ -0,0 +1,38 @@
+package foreground
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// Window describes the application that owns the focused window
+type Window struct {
+	Executable string // File name of the process, e.g. "firefox.exe"
+	Class      string // Window class, empty when the platform has none
+}
+
+// String returns a short description for logs
+func (w Window) String() string {
+	if w.Class == "" || strings.EqualFold(w.Class, w.Executable) {
+		return w.Executable
+	}
+	return w.Executable + " (" + w.Class + ")"
+}
+
+// Matches reports whether pattern names the executable or the window
+// class, ignoring case. An executable also matches without its extension.
+func (w Window) Matches(pattern string) bool {
+	pattern = strings.TrimSpace(pattern)
+	if pattern == "" {
+		return false
+	}
+	exe := w.Executable
+	return strings.EqualFold(pattern, exe) ||
+		strings.EqualFold(pattern, strings.TrimSuffix(exe, filepath.Ext(exe))) ||
+		strings.EqualFold(pattern, w.Class)
+}
+
+// Active returns the application in the foreground
+func Active() (Window, error) {
+	return active()
+}
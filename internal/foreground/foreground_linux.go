@@ -0,0 +1,50 @@
+//go:build linux
+
+package foreground
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	activeWindowPattern = regexp.MustCompile(`window id # (0x[0-9a-fA-F]+)`)
+	classPattern        = regexp.MustCompile(`WM_CLASS = "[^"]*", "([^"]*)"`)
+	pidPattern          = regexp.MustCompile(`_NET_WM_PID = (\d+)`)
+)
+
+// active asks the X server, or XWayland, for _NET_ACTIVE_WINDOW through
+// xprop. Native Wayland windows are not visible this way.
+func active() (Window, error) {
+	out, err := exec.Command("xprop", "-root", "-notype", "_NET_ACTIVE_WINDOW").Output()
+	if err != nil {
+		return Window{}, fmt.Errorf("failed to query the active window (is xprop installed?): %v", err)
+	}
+	m := activeWindowPattern.FindSubmatch(out)
+	if m == nil || string(m[1]) == "0x0" {
+		return Window{}, fmt.Errorf("no window has the focus")
+	}
+
+	out, err = exec.Command("xprop", "-id", string(m[1]), "-notype", "WM_CLASS", "_NET_WM_PID").Output()
+	if err != nil {
+		return Window{}, fmt.Errorf("failed to query window %s: %v", m[1], err)
+	}
+
+	var w Window
+	if m := classPattern.FindSubmatch(out); m != nil {
+		w.Class = string(m[1])
+	}
+	if m := pidPattern.FindSubmatch(out); m != nil {
+		if exe, err := os.Readlink(filepath.Join("/proc", string(m[1]), "exe")); err == nil {
+			w.Executable = filepath.Base(strings.TrimSuffix(exe, " (deleted)"))
+		}
+	}
+	if w.Executable == "" && w.Class == "" {
+		return w, fmt.Errorf("window %s has no class or process", m[1])
+	}
+	return w, nil
+}
@@ -0,0 +1,9 @@
+//go:build !windows && !linux
+
+package foreground
+
+import "fmt"
+
+func active() (Window, error) {
+	return Window{}, fmt.Errorf("foreground application detection not supported on this platform")
+}
@@ -0,0 +1,36 @@
+package foreground
+
+import "testing"
+
+func TestWindowMatches(t *testing.T) {
+	firefox := Window{Executable: "firefox.exe", Class: "MozillaWindowClass"}
+	game := Window{Executable: "Game.Launcher.x86_64"}
+
+	tests := []struct {
+		window  Window
+		pattern string
+		want    bool
+	}{
+		{firefox, "firefox.exe", true},
+		{firefox, "firefox", true},
+		{firefox, "FireFox.EXE", true},
+		{firefox, "  firefox  ", true},
+		{firefox, "mozillawindowclass", true},
+		{firefox, "fire", false},
+		{firefox, "firefox.bin", false},
+		{firefox, "", false},
+		{firefox, "   ", false},
+		// Only the last extension is optional
+		{game, "game.launcher", true},
+		{game, "game", false},
+		// A blank pattern never matches a window without a class
+		{game, "", false},
+		{Window{}, "", false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.window.Matches(tt.pattern); got != tt.want {
+			t.Errorf("%v matches %q = %v, want %v", tt.window, tt.pattern, got, tt.want)
+		}
+	}
+}
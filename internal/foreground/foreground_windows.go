@@ -0,0 +1,41 @@
+//go:build windows
+
+package foreground
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"golang.org/x/sys/windows"
+)
+
+func active() (Window, error) {
+	hwnd := windows.GetForegroundWindow()
+	if hwnd == 0 {
+		return Window{}, fmt.Errorf("no window has the focus")
+	}
+
+	var w Window
+	class := make([]uint16, 256)
+	if n, err := windows.GetClassName(hwnd, &class[0], int32(len(class))); err == nil {
+		w.Class = windows.UTF16ToString(class[:n])
+	}
+
+	var pid uint32
+	if _, err := windows.GetWindowThreadProcessId(hwnd, &pid); err != nil {
+		return w, fmt.Errorf("failed to get window process: %v", err)
+	}
+	process, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, pid)
+	if err != nil {
+		return w, fmt.Errorf("failed to open process %d: %v", pid, err)
+	}
+	defer windows.CloseHandle(process)
+
+	name := make([]uint16, windows.MAX_LONG_PATH)
+	size := uint32(len(name))
+	if err := windows.QueryFullProcessImageName(process, 0, &name[0], &size); err != nil {
+		return w, fmt.Errorf("failed to get process image name: %v", err)
+	}
+	w.Executable = filepath.Base(windows.UTF16ToString(name[:size]))
+	return w, nil
+}
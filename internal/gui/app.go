@@ -3,6 +3,8 @@ package gui
 import (
 	"fmt"
 	"image/color"
+	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
@@ -16,11 +18,18 @@ import (
 	"fyne.io/systray"
 
 	"click-guardian/internal/config"
+	"click-guardian/internal/control"
+	"click-guardian/internal/filter"
 	"click-guardian/internal/gui/components"
 	"click-guardian/internal/gui/dialogs"
 	"click-guardian/internal/gui/resources"
+	"click-guardian/internal/health"
+	"click-guardian/internal/history"
 	"click-guardian/internal/hooks"
 	"click-guardian/internal/logger"
+	"click-guardian/internal/metrics"
+	"click-guardian/internal/profiles"
+	"click-guardian/internal/trace"
 	"click-guardian/internal/version"
 	"click-guardian/pkg/platform"
 )
@@ -30,6 +39,8 @@ type Application struct {
 	app              fyne.App
 	window           fyne.Window
 	hook             hooks.MouseHook
+	keyboard         hooks.KeyboardHook
+	hotkeys          hooks.HotkeyHook
 	logger           *logger.Logger
 	config           *config.Config
 	isRunning        bool
@@ -43,13 +54,73 @@ type Application struct {
 	statusIcon          *canvas.Circle
 	counterText         *canvas.Text
 	blockedLabelText    *canvas.Text
+	wheelCounterText    *canvas.Text
+	keyCounterText      *canvas.Text
+	wheelFilterCheck    *widget.Check
+	wheelWindowSlider   *widget.Slider
+	wheelWindowLabel    *widget.Label
+	keyboardCheck       *widget.Check
+	keyThresholdSlider  *widget.Slider
+	keyThresholdLabel   *widget.Label
+	hotkeyEntry         *widget.Entry
+	bypassSelect        *widget.Select
+	strategyRadio       *widget.RadioGroup
+	doubleClickSlider   *widget.Slider
+	doubleClickLabel    *widget.Label
+	doubleClickBox      *fyne.Container // Double-click window caption, slider and value
 	toggleButton        *widget.Button
+	recordButton        *widget.Button
 	logText             *widget.RichText
 	logContainer        *container.Scroll
 	minimizeToTrayCheck *widget.Check
 	autoStartCheck      *widget.Check
-	updateChan     chan int
-	updateChanOnce sync.Once
+	buttonChecks        map[filter.Button]*widget.Check
+	buttonDelaySliders  map[filter.Button]*widget.Slider
+	buttonDelayLabels   map[filter.Button]*widget.Label
+	profileList         *fyne.Container // One row per application profile
+	deviceList          *fyne.Container // One row per mouse
+	deviceControls      []fyne.Disableable
+	updateChan          chan filter.Stats
+	updateChanOnce      sync.Once
+
+	// Structured events published by the mouse hook
+	hookEvents chan hooks.Event
+
+	// Local control socket, nil when it could not be opened
+	control *control.Server
+
+	// Lifetime statistics, nil when the history could not be opened
+	history          *history.Store
+	historyCollector *history.Collector
+
+	// Statistics and Test tabs
+	statsView *statisticsView
+	testPad   *testPad
+
+	// Latest button health scores, only accessed on the main thread
+	healthReports []health.Report
+
+	// Switches the hook settings with the foreground application, nil while
+	// stopped or without profiles
+	profileWatcher *profiles.Watcher
+	activeProfile  string // Name of the active profile, only accessed on the main thread
+
+	// Pending snooze, see snooze
+	snoozeMu         sync.Mutex
+	snoozeTimer      *time.Timer // Resumes protection, nil unless snoozed
+	snoozeGen        uint64      // Changed by every snooze and cancellation
+	snoozedUntil     time.Time
+	lastSnoozeStatus string // Snooze status in the tray tooltip, only accessed by handleUIUpdates
+
+	// Prometheus exporter, nil unless enabled in the config
+	metrics *metrics.Server
+
+	// Trace of raw events being recorded, nil when not recording
+	recorder *trace.Recorder
+
+	// Consumers of raw hook events, see addObserver
+	observerMu sync.Mutex
+	observers  map[string]filter.Observer
 
 	// System tray
 	trayRestore *systray.MenuItem
@@ -86,11 +157,14 @@ func NewApplication() *Application {
 		app:                   a,
 		window:                w,
 		hook:                  hooks.NewMouseHook(),
+		keyboard:              hooks.NewKeyboardHook(),
+		hotkeys:               hooks.NewHotkeyHook(),
 		logger:                logger,
 		config:                cfg,
 		logText:               logText,
 		logContainer:          logContainer,
-		updateChan:            make(chan int, 10),
+		updateChan:            make(chan filter.Stats, 10),
+		hookEvents:            make(chan hooks.Event, 100),
 		shutdownChan:          make(chan struct{}),
 		minimizeToTrayEnabled: cfg.MinimizeToTray, // Use saved preference
 	}
@@ -101,6 +175,10 @@ func (app *Application) Run() {
 	app.setupUI()
 	app.setupSystemTray()
 	app.logger.Start()
+	app.startHistory()
+	app.startControlServer()
+	app.startMetricsServer()
+	app.startHotkeys()
 
 	// Initialize log
 	app.logger.Log("Click Guardian application started")
@@ -117,6 +195,9 @@ func (app *Application) Run() {
 	// Start a goroutine to handle UI updates safely
 	go app.handleUIUpdates()
 
+	// Start a goroutine to log events published by the hook
+	go app.handleHookEvents()
+
 	// Set window close behavior based on stored state (avoid UI thread issues)
 	app.window.SetCloseIntercept(app.handleWindowClose)
 
@@ -131,6 +212,10 @@ func (app *Application) RunMinimized() {
 	app.setupUI()
 	app.setupSystemTray()
 	app.logger.Start()
+	app.startHistory()
+	app.startControlServer()
+	app.startMetricsServer()
+	app.startHotkeys()
 
 	// Initialize log
 	app.logger.Log("Click Guardian application started (minimized)")
@@ -155,6 +240,9 @@ func (app *Application) RunMinimized() {
 	// Start a goroutine to handle UI updates safely
 	go app.handleUIUpdates()
 
+	// Start a goroutine to log events published by the hook
+	go app.handleHookEvents()
+
 	// Set window close behavior based on stored state (avoid UI thread issues)
 	app.window.SetCloseIntercept(app.handleWindowClose)
 
@@ -190,6 +278,10 @@ func (app *Application) RunWithAutoProtect() {
 	app.setupUI()
 	app.setupSystemTray()
 	app.logger.Start()
+	app.startHistory()
+	app.startControlServer()
+	app.startMetricsServer()
+	app.startHotkeys()
 
 	// Initialize log
 	app.logger.Log("Click Guardian application started with auto-protect")
@@ -214,6 +306,9 @@ func (app *Application) RunWithAutoProtect() {
 	// Start a goroutine to handle UI updates safely
 	go app.handleUIUpdates()
 
+	// Start a goroutine to log events published by the hook
+	go app.handleHookEvents()
+
 	// Set window close behavior based on stored state (avoid UI thread issues)
 	app.window.SetCloseIntercept(app.handleWindowClose)
 
@@ -246,18 +341,36 @@ func (app *Application) setupUI() {
 	app.blockedLabelText.Alignment = fyne.TextAlignCenter
 	app.blockedLabelText.TextSize = 14
 
+	// Wheel glitch counter, only shown while wheel filtering is enabled
+	app.wheelCounterText = canvas.NewText("Wheel glitches: 0", color.White)
+	app.wheelCounterText.Alignment = fyne.TextAlignCenter
+	app.wheelCounterText.TextSize = 12
+	if !app.config.WheelFilterEnabled {
+		app.wheelCounterText.Hide()
+	}
+
+	// Key chatter counter, only shown while keyboard filtering is enabled
+	app.keyCounterText = canvas.NewText("Key chatter: 0", color.White)
+	app.keyCounterText.Alignment = fyne.TextAlignCenter
+	app.keyCounterText.TextSize = 12
+	if !app.config.KeyboardEnabled {
+		app.keyCounterText.Hide()
+	}
+
 	// Create the status indicator by stacking the circle and the labels
 	statusIndicator := container.NewStack(
 		app.statusIcon,
 		container.NewCenter(container.NewVBox(
 			app.blockedLabelText,
 			app.counterText,
+			app.wheelCounterText,
+			app.keyCounterText,
 			layout.NewSpacer(),
 		)),
 	)
 
-	// Delay slider with min 5ms, max 500ms
-	app.delaySlider = widget.NewSlider(5, 500)
+	// Delay slider over the accepted delay range
+	app.delaySlider = widget.NewSlider(config.MinDelayMs, config.MaxDelayMs)
 	app.delaySlider.SetValue(float64(app.config.DelayMs))
 	app.delaySlider.Step = 5 // 5ms increments
 
@@ -273,6 +386,121 @@ func (app *Application) setupUI() {
 		if err := app.config.Save(); err != nil {
 			app.logger.Log("⚠️ Failed to save delay setting: %v", err)
 		}
+		// Buttons without their own delay follow the default
+		app.refreshButtonDelayLabels()
+	}
+
+	// One checkbox per mouse button to choose which buttons are filtered
+	app.buttonChecks = make(map[filter.Button]*widget.Check)
+	buttonRow := container.NewHBox()
+	for _, button := range filter.Buttons() {
+		check := widget.NewCheck(button.String(), func(checked bool) {
+			app.config.SetButtonEnabled(button, checked)
+			if err := app.config.Save(); err != nil {
+				app.logger.Log("⚠️ Failed to save button setting: %v", err)
+			}
+		})
+		check.SetChecked(app.config.ButtonEnabled(button))
+		app.buttonChecks[button] = check
+		buttonRow.Add(check)
+	}
+
+	// Per-button delay sliders (0 = use the default delay above)
+	app.buttonDelaySliders = make(map[filter.Button]*widget.Slider)
+	app.buttonDelayLabels = make(map[filter.Button]*widget.Label)
+	buttonDelayForm := container.NewVBox()
+	for _, button := range filter.Buttons() {
+		slider := widget.NewSlider(0, config.MaxDelayMs)
+		slider.Step = 5
+		slider.SetValue(float64(app.config.Buttons[config.ButtonKey(button)].DelayMs))
+		label := widget.NewLabel("")
+		slider.OnChanged = func(value float64) {
+			delayMs := int(value)
+			if delayMs > 0 && delayMs < config.MinDelayMs {
+				delayMs = config.MinDelayMs
+			}
+			app.config.SetButtonDelayMs(button, delayMs)
+			app.refreshButtonDelayLabels()
+			if err := app.config.Save(); err != nil {
+				app.logger.Log("⚠️ Failed to save button delay setting: %v", err)
+			}
+		}
+		app.buttonDelaySliders[button] = slider
+		app.buttonDelayLabels[button] = label
+		buttonDelayForm.Add(container.NewBorder(nil, nil,
+			widget.NewLabel(button.String()), label, slider))
+	}
+	app.refreshButtonDelayLabels()
+	buttonDelayAccordion := widget.NewAccordion(
+		widget.NewAccordionItem("Per-button delays", buttonDelayForm),
+	)
+
+	// Scroll wheel glitch filter with its own window slider (10ms - 500ms)
+	app.wheelWindowSlider = widget.NewSlider(10, 500)
+	app.wheelWindowSlider.SetValue(float64(app.config.WheelWindowMs))
+	app.wheelWindowSlider.Step = 10
+	app.wheelWindowLabel = widget.NewLabel(fmt.Sprintf("%d ms", app.config.WheelWindowMs))
+	app.wheelWindowLabel.Alignment = fyne.TextAlignCenter
+	app.wheelWindowSlider.OnChanged = func(value float64) {
+		app.wheelWindowLabel.SetText(fmt.Sprintf("%.0f ms", value))
+		app.config.WheelWindowMs = int(value)
+		if err := app.config.Save(); err != nil {
+			app.logger.Log("⚠️ Failed to save wheel window setting: %v", err)
+		}
+	}
+
+	app.wheelFilterCheck = widget.NewCheck("Filter scroll wheel direction glitches", func(checked bool) {
+		app.config.WheelFilterEnabled = checked
+		if checked {
+			app.wheelCounterText.Show()
+		} else {
+			app.wheelCounterText.Hide()
+		}
+		if err := app.config.Save(); err != nil {
+			app.logger.Log("⚠️ Failed to save wheel filter setting: %v", err)
+		}
+	})
+	app.wheelFilterCheck.SetChecked(app.config.WheelFilterEnabled)
+
+	// Filter strategy with the double-click window it uses (100ms - 1000ms)
+	app.doubleClickSlider = widget.NewSlider(config.MinDoubleClickWindowMs, config.MaxDoubleClickWindowMs)
+	app.doubleClickSlider.SetValue(float64(app.config.DoubleClickWindowMs))
+	app.doubleClickSlider.Step = 10
+	app.doubleClickLabel = widget.NewLabel(fmt.Sprintf("%d ms", app.config.DoubleClickWindowMs))
+	app.doubleClickLabel.Alignment = fyne.TextAlignCenter
+	app.doubleClickBox = container.NewVBox(
+		widget.NewLabel("Double-click window (ms):"),
+		app.doubleClickSlider,
+		container.NewCenter(app.doubleClickLabel),
+	)
+	app.doubleClickSlider.OnChanged = func(value float64) {
+		app.doubleClickLabel.SetText(fmt.Sprintf("%.0f ms", value))
+		app.config.DoubleClickWindowMs = int(value)
+		if err := app.config.Save(); err != nil {
+			app.logger.Log("⚠️ Failed to save double-click window setting: %v", err)
+		}
+	}
+
+	strategyOptions := make([]string, 0, len(filter.Strategies()))
+	for _, strategy := range filter.Strategies() {
+		strategyOptions = append(strategyOptions, strategyLabel(strategy))
+	}
+	app.strategyRadio = widget.NewRadioGroup(strategyOptions, nil)
+	app.strategyRadio.Horizontal = true
+	app.strategyRadio.Required = true
+	strategy, _ := filter.ParseStrategy(app.config.FilterStrategy)
+	app.strategyRadio.SetSelected(strategyLabel(strategy))
+	app.showDoubleClickWindow(strategy)
+	app.strategyRadio.OnChanged = func(selected string) {
+		for _, strategy := range filter.Strategies() {
+			if strategyLabel(strategy) == selected {
+				app.config.FilterStrategy = strategy.String()
+				app.showDoubleClickWindow(strategy)
+			}
+		}
+		if err := app.config.Save(); err != nil {
+			app.logger.Log("⚠️ Failed to save filter strategy setting: %v", err)
+		}
 	}
 
 	// Minimize to tray checkbox
@@ -300,6 +528,12 @@ func (app *Application) setupUI() {
 		app.logger.Clear()
 	})
 
+	// Record button captures raw events for offline replay
+	app.recordButton = widget.NewButton("Record", app.toggleRecording)
+
+	// Calibrate button measures the mouse and recommends per-button delays
+	calibrateButton := widget.NewButton("Calibrate...", app.showCalibrationWizard)
+
 	// About button
 	aboutButton := widget.NewButton("About", func() {
 		dialogs.ShowAboutDialog(app.window)
@@ -310,15 +544,18 @@ func (app *Application) setupUI() {
 	// Main control section with toggle button
 	controlSection := container.NewVBox(
 		container.NewCenter(app.toggleButton),
+		app.setupSnoozeRow(),
 	)
 
 	// Status and statistics section with the new indicator
 	hoverIndicator := components.NewHoverAware(statusIndicator, func() string {
+		status := "Status: Inactive"
 		if app.isRunning {
-			return "Status: Active"
-		} else {
-			return "Status: Inactive"
+			status = "Status: Active"
+		} else if snooze := app.snoozeStatus(); snooze != "" {
+			status = "Status: Snoozed, " + snooze
 		}
+		return status + app.statsBreakdown()
 	})
 	statusIndicatorContainer := container.NewGridWrap(fyne.NewSize(200, 200), hoverIndicator)
 
@@ -334,10 +571,30 @@ func (app *Application) setupUI() {
 
 	configContent := container.NewVBox(
 		container.NewVBox(
-			widget.NewLabel("Delay (ms):"),
+			container.NewBorder(nil, nil, widget.NewLabel("Delay (ms):"), calibrateButton),
 			app.delaySlider,
 			container.NewCenter(app.delayValueLabel),
+			buttonDelayAccordion,
+		),
+		app.setupDevicesSection(),
+		app.setupProfilesSection(),
+		container.NewVBox(
+			widget.NewLabel("Filtered buttons:"),
+			buttonRow,
+		),
+		container.NewVBox(
+			widget.NewLabel("Double-clicks within the delay:"),
+			app.strategyRadio,
+			app.doubleClickBox,
 		),
+		container.NewVBox(
+			app.wheelFilterCheck,
+			widget.NewLabel("Wheel reversal window (ms):"),
+			app.wheelWindowSlider,
+			container.NewCenter(app.wheelWindowLabel),
+		),
+		app.setupKeyboardSection(),
+		app.setupHotkeySection(),
 		app.minimizeToTrayCheck,
 		app.autoStartCheck,
 	)
@@ -351,7 +608,7 @@ func (app *Application) setupUI() {
 	logTitle := canvas.NewText("Activity Log", color.White)
 	logTitle.TextStyle = fyne.TextStyle{Bold: true}
 	logTitle.TextSize = 16
-	logHeader := container.NewHBox(widget.NewIcon(theme.ListIcon()), logTitle, layout.NewSpacer(), clearButton, aboutButton)
+	logHeader := container.NewHBox(widget.NewIcon(theme.ListIcon()), logTitle, layout.NewSpacer(), app.recordButton, clearButton, aboutButton)
 
 	logSection := widget.NewCard("", "", container.NewVBox(
 		logHeader,
@@ -370,7 +627,25 @@ func (app *Application) setupUI() {
 		logSection,
 	)
 
-	app.window.SetContent(content)
+	tabs := container.NewAppTabs(
+		// The settings are taller than the fixed-size window
+		container.NewTabItemWithIcon("Protection", theme.HomeIcon(), container.NewVScroll(content)),
+		container.NewTabItemWithIcon("Statistics", theme.InfoIcon(), app.setupStatisticsTab()),
+		container.NewTabItemWithIcon("Test", theme.RadioButtonCheckedIcon(), app.setupTestPadTab()),
+	)
+	tabs.OnSelected = func(tab *container.TabItem) {
+		if tab.Text == "Statistics" {
+			app.refreshStatistics()
+		}
+		// The test pad only listens to the hook while it is shown
+		if tab.Text == "Test" {
+			app.startTestPad()
+		} else {
+			app.stopTestPad()
+		}
+	}
+
+	app.window.SetContent(tabs)
 	app.window.Resize(fyne.NewSize(float32(app.config.WindowWidth), float32(app.config.WindowHeight)))
 	app.window.SetFixedSize(true) // Disable resizing and maximize button
 	app.window.CenterOnScreen()
@@ -389,6 +664,7 @@ func (app *Application) startProtection() {
 	if app.isRunning {
 		return
 	}
+	app.cancelSnooze()
 
 	if !app.hook.IsSupported() {
 		app.logger.Log("❌ Mouse hooking not supported on this platform")
@@ -400,8 +676,22 @@ func (app *Application) startProtection() {
 
 	// Update UI elements on main thread
 	fyne.Do(func() {
-		// Disable slider when protection is active
+		// Disable slider and button selection when protection is active
 		app.delaySlider.Disable()
+		for _, check := range app.buttonChecks {
+			check.Disable()
+		}
+		for _, slider := range app.buttonDelaySliders {
+			slider.Disable()
+		}
+		for _, control := range app.deviceControls {
+			control.Disable()
+		}
+		app.wheelFilterCheck.Disable()
+		app.wheelWindowSlider.Disable()
+		app.strategyRadio.Disable()
+		app.doubleClickSlider.Disable()
+		app.keyThresholdSlider.Disable()
 
 		app.statusIcon.FillColor = color.RGBA{R: 40, G: 167, B: 69, A: 255} // Green for active
 		app.statusIcon.Refresh()
@@ -412,8 +702,17 @@ func (app *Application) startProtection() {
 
 	app.isRunning = true
 	app.logger.Log("Starting double-click protection with %d ms delay", delayMs)
+	for _, button := range filter.Buttons() {
+		if own := app.config.Buttons[config.ButtonKey(button)].DelayMs; own > 0 {
+			app.logger.Log("%s button uses its own %d ms delay", button, own)
+		}
+	}
+
+	filterConfig := app.config.FilterConfig()
+	filterConfig.Delay = time.Duration(delayMs) * time.Millisecond
+	app.resetDelayTimeline(filterConfig)
 
-	err := app.hook.Start(time.Duration(delayMs)*time.Millisecond, app.logger.GetChannel())
+	err := app.hook.Start(filterConfig, app.hookEvents)
 	if err != nil {
 		app.logger.Log("❌ Failed to start protection: %v", err)
 		fyne.Do(func() {
@@ -423,19 +722,39 @@ func (app *Application) startProtection() {
 		// app.statusLabel.SetText("Protection Failed")
 		app.resetUI()
 	} else {
+		if len(app.config.Profiles) > 0 {
+			app.logger.Log("Watching the foreground application for %d profiles", len(app.config.Profiles))
+		}
+		app.startProfileWatcher()
+		if app.config.KeyboardEnabled {
+			app.startKeyboardFilter()
+		}
+		if app.recorder != nil {
+			app.recorder.Started()
+		}
 		// Update tray tooltip when protection starts successfully
 		app.updateTrayTooltip()
 	}
 }
 
 func (app *Application) stopProtection() {
+	if app.cancelSnooze() && !app.isRunning {
+		app.logger.Log("Snooze cancelled, protection stays stopped")
+		app.resetUI()
+		return
+	}
 	if !app.isRunning {
 		return
 	}
 
 	app.isRunning = false
 	app.logger.Log("Stopping double-click protection")
+	app.stopProfileWatcher()
+	app.stopKeyboardFilter()
 	app.hook.Stop()
+	if app.recorder != nil {
+		app.recorder.Stopped()
+	}
 	app.resetUI()
 }
 
@@ -449,14 +768,107 @@ func (app *Application) resetUI() {
 		app.toggleButton.SetText("Start Protection")
 		app.toggleButton.Importance = widget.HighImportance
 
-		// Re-enable slider when protection is stopped
+		// Re-enable slider and button selection when protection is stopped
 		app.delaySlider.Enable()
+		for _, check := range app.buttonChecks {
+			check.Enable()
+		}
+		for _, slider := range app.buttonDelaySliders {
+			slider.Enable()
+		}
+		for _, control := range app.deviceControls {
+			control.Enable()
+		}
+		app.wheelFilterCheck.Enable()
+		app.wheelWindowSlider.Enable()
+		app.strategyRadio.Enable()
+		app.doubleClickSlider.Enable()
+		app.keyThresholdSlider.Enable()
 	})
 
 	// Update tray tooltip when protection stops
 	app.updateTrayTooltip()
 }
 
+// strategyLabel returns the option shown for a filter strategy
+func strategyLabel(strategy filter.Strategy) string {
+	if strategy == filter.StrategyDoubleClick {
+		return "Allow intentional"
+	}
+	return "Block (strict)"
+}
+
+// showDoubleClickWindow shows the double-click window only for the
+// strategy that uses it
+func (app *Application) showDoubleClickWindow(strategy filter.Strategy) {
+	if strategy == filter.StrategyDoubleClick {
+		app.doubleClickBox.Show()
+	} else {
+		app.doubleClickBox.Hide()
+	}
+}
+
+// toggleRecording starts or stops recording an event trace
+func (app *Application) toggleRecording() {
+	if app.recorder != nil {
+		app.stopRecording()
+		return
+	}
+
+	tracesDir, err := config.GetTracesDir()
+	if err != nil {
+		app.logger.Log("❌ Failed to start recording: %v", err)
+		return
+	}
+
+	filterConfig := app.config.FilterConfig()
+	filterConfig.Delay = time.Duration(app.delaySlider.Value) * time.Millisecond
+
+	path := filepath.Join(tracesDir, "trace-"+time.Now().Format("20060102-150405")+".jsonl")
+	recorder, err := trace.NewRecorder(path, filterConfig)
+	if err != nil {
+		app.logger.Log("❌ Failed to start recording: %v", err)
+		return
+	}
+
+	app.recorder = recorder
+	app.addObserver("recorder", recorder.Observe)
+	if !app.isRunning {
+		// Clicks are only recorded while protection runs
+		recorder.Stopped()
+	}
+	app.recordButton.SetText("Stop Recording")
+	app.recordButton.Importance = widget.WarningImportance
+	app.recordButton.Refresh()
+	app.logger.Log("⏺ Recording events to %s", path)
+}
+
+// stopRecording finishes the current trace, if any
+func (app *Application) stopRecording() {
+	if app.recorder == nil {
+		return
+	}
+
+	app.removeObserver("recorder")
+	recorder := app.recorder
+	app.recorder = nil
+
+	dropped, err := recorder.Close()
+	if err != nil {
+		app.logger.Log("❌ Failed to save trace: %v", err)
+	} else if dropped > 0 {
+		app.logger.Log("⏹ Trace saved to %s (%d events dropped)", recorder.Path(), dropped)
+	} else {
+		app.logger.Log("⏹ Trace saved to %s", recorder.Path())
+	}
+
+	fyne.Do(func() {
+		app.recordButton.SetText("Record")
+		app.recordButton.Importance = widget.MediumImportance
+		app.recordButton.Refresh()
+	})
+}
+
 func (app *Application) cleanup() {
 	app.shutdownOnce.Do(func() {
 		fmt.Println("Cleanup called from main thread")
@@ -482,6 +894,7 @@ func (app *Application) onTrayReady() {
 	app.updateTrayTooltip()
 
 	app.trayRestore = systray.AddMenuItem("Show Click Guardian", "Restore the application window")
+	app.addTraySnoozeMenu()
 	systray.AddSeparator()
 	app.trayQuit = systray.AddMenuItem("Quit Application", "Completely quit the application")
 
@@ -574,9 +987,25 @@ func (app *Application) quitApplication() {
 	// Stop everything immediately and directly
 	if app.isRunning {
 		fmt.Println("Stopping mouse hook protection...")
+		app.profileWatcher.Stop()
+		app.keyboard.Stop()
 		app.hook.Stop()
 		app.isRunning = false
 	}
+	app.cancelSnooze()
+	app.hotkeys.Stop()
+	app.stopRecording()
+	if app.control != nil {
+		app.control.Close()
+	}
+	if app.metrics != nil {
+		app.metrics.Close()
+	}
+	if app.historyCollector != nil {
+		if err := app.historyCollector.Flush(); err != nil {
+			fmt.Printf("Failed to save statistics: %v\n", err)
+		}
+	}
 
 	// Signal all goroutines to stop
 	func() {
@@ -639,10 +1068,10 @@ func (app *Application) updateBlockedClicksCounter() {
 		select {
 		case <-ticker.C:
 			if app.hook != nil {
-				count := app.hook.GetBlockedCount()
-				// Send the count to the UI update channel
+				stats := app.hook.Stats()
+				// Send the statistics to the UI update channel
 				select {
-				case app.updateChan <- count:
+				case app.updateChan <- stats:
 				default:
 					// Don't block if channel is full
 				}
@@ -656,7 +1085,16 @@ func (app *Application) updateBlockedClicksCounter() {
 
 // handleUIUpdates safely handles UI updates from the main thread
 func (app *Application) handleUIUpdates() {
-	for count := range app.updateChan {
+	for stats := range app.updateChan {
+		count := stats.TotalBlockedClicks()
+		keyBlocked := app.keyboard.Stats().TotalBlocked()
+		// Counts the remaining snooze down in the tray tooltip
+		if snooze := app.snoozeStatus(); snooze != app.lastSnoozeStatus {
+			app.lastSnoozeStatus = snooze
+			if snooze != "" {
+				app.updateTrayTooltip()
+			}
+		}
 		fyne.Do(func() {
 			// Animate if the count has increased
 			if count > app.lastBlockedCount {
@@ -684,10 +1122,98 @@ func (app *Application) handleUIUpdates() {
 			app.lastBlockedCount = count
 			app.counterText.Text = fmt.Sprintf("%d", count)
 			app.counterText.Refresh()
+			app.wheelCounterText.Text = fmt.Sprintf("Wheel glitches: %d", stats.WheelBlocked)
+			app.wheelCounterText.Refresh()
+			app.keyCounterText.Text = fmt.Sprintf("Key chatter: %d", keyBlocked)
+			app.keyCounterText.Refresh()
 		})
 	}
 }
 
+// handleHookEvents formats hook events for the log and reacts to hook failures
+func (app *Application) handleHookEvents() {
+	for {
+		select {
+		case ev := <-app.hookEvents:
+			app.logger.LogEvent(ev)
+			app.publishControlEvent(ev)
+			if ev.Type == hooks.EventDelayAdjusted {
+				app.recordDelayChange(ev.Button, ev.Time, ev.Delay)
+			}
+			if ev.Type == hooks.EventDeviceGrabbed {
+				fyne.Do(app.refreshDeviceList)
+			}
+			if ev.Type == hooks.EventHotkey {
+				fyne.Do(app.toggleProtection)
+			}
+
+			// Losing a single device is survivable, losing the hook is not
+			if ev.Type == hooks.EventError && ev.Fatal {
+				fyne.Do(func() {
+					if !app.isRunning {
+						return
+					}
+					app.stopProfileWatcher()
+					app.stopKeyboardFilter()
+					app.hook.Stop()
+					app.resetUI()
+				})
+			}
+		case <-app.shutdownChan:
+			// Graceful shutdown
+			return
+		}
+	}
+}
+
+// refreshButtonDelayLabels shows each button's own delay or the default it follows
+func (app *Application) refreshButtonDelayLabels() {
+	for button, label := range app.buttonDelayLabels {
+		if own := app.config.Buttons[config.ButtonKey(button)].DelayMs; own > 0 {
+			label.SetText(fmt.Sprintf("%d ms", own))
+		} else {
+			label.SetText(fmt.Sprintf("default (%d ms)", app.config.DelayMs))
+		}
+	}
+}
+
+// statsBreakdown describes allowed and blocked counts per button and reason
+func (app *Application) statsBreakdown() string {
+	stats := app.hook.Stats()
+
+	var sb strings.Builder
+	for _, button := range filter.Buttons() {
+		allowed := stats.Allowed[button]
+		blocked := stats.Blocked[button]
+		if allowed == 0 && len(blocked) == 0 {
+			continue
+		}
+		fmt.Fprintf(&sb, "\n%s: %d allowed, %d blocked", button, allowed, stats.BlockedClicks(button))
+		for _, reason := range filter.BlockReasons() {
+			if n := blocked[reason]; n > 0 {
+				fmt.Fprintf(&sb, "\n  %s: %d", reason, n)
+			}
+		}
+	}
+	if stats.WheelAllowed > 0 || stats.WheelBlocked > 0 {
+		fmt.Fprintf(&sb, "\nWheel: %d ticks, %d glitches blocked", stats.WheelAllowed+stats.WheelBlocked, stats.WheelBlocked)
+	}
+	sb.WriteString(app.keyStatsBreakdown())
+
+	if app.history != nil {
+		if days := app.history.Days(0); len(days) > 0 {
+			blocked := 0
+			for _, day := range days {
+				for _, b := range day.Buttons {
+					blocked += b.BlockedClicks()
+				}
+			}
+			fmt.Fprintf(&sb, "\nLifetime: %d blocked since %s", blocked, days[0].Date)
+		}
+	}
+	return sb.String()
+}
+
 // updateTrayTooltip updates the system tray tooltip with current status and blocked count
 func (app *Application) updateTrayTooltip() {
 	// Ensure this runs on the main thread
@@ -695,9 +1221,14 @@ func (app *Application) updateTrayTooltip() {
 		if app.isRunning {
 			blockedCount := app.hook.GetBlockedCount()
 			tooltip := fmt.Sprintf("Click Guardian - Active\nBlocked clicks: %d", blockedCount)
-			systray.SetTooltip(tooltip)
+			if app.activeProfile != "" {
+				tooltip += fmt.Sprintf("\nProfile: %s", app.activeProfile)
+			}
+			systray.SetTooltip(tooltip + healthTooltip(app.healthReports))
+		} else if snooze := app.snoozeStatus(); snooze != "" {
+			systray.SetTooltip("Click Guardian - Snoozed\nProtection " + snooze + healthTooltip(app.healthReports))
 		} else {
-			systray.SetTooltip("Click Guardian - Inactive")
+			systray.SetTooltip("Click Guardian - Inactive" + healthTooltip(app.healthReports))
 		}
 	})
 }
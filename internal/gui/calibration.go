@@ -0,0 +1,175 @@
+package gui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"click-guardian/internal/calibration"
+	"click-guardian/internal/gui/components"
+)
+
+const (
+	calibrationPoll   = 100 * time.Millisecond // How often the wizard checks the progress of a step
+	calibrationLinger = 500 * time.Millisecond // Time after the last action so its chatter is still recorded
+)
+
+// showCalibrationWizard guides the user through clicks, double-clicks and
+// drags on a test pad and offers to apply the delays recommended for each
+// button that was used
+func (app *Application) showCalibrationWizard() {
+	// Raw timings come from the hook, so it has to run during calibration
+	startedHere := false
+	if !app.isRunning {
+		app.startProtection()
+		if !app.isRunning {
+			dialog.ShowError(fmt.Errorf("protection could not be started, see the activity log"), app.window)
+			return
+		}
+		startedHere = true
+	}
+
+	session := calibration.NewSession()
+	app.addObserver("calibration", session.Observe)
+	app.logger.Log("🎯 Calibration started")
+
+	steps := calibration.Steps()
+	stepIndex := 0
+	finished := false
+	applied := false
+	var completeAt time.Time
+	var results []calibration.Result
+
+	title := widget.NewLabelWithStyle("", fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	instructions := widget.NewLabel("")
+	instructions.Wrapping = fyne.TextWrapWord
+	progress := widget.NewProgressBar()
+	progress.TextFormatter = func() string {
+		return fmt.Sprintf("%.0f of %.0f", progress.Value, progress.Max)
+	}
+	pad := components.NewClickPad("Use the button you want to calibrate")
+	summary := widget.NewLabel("")
+	summary.Wrapping = fyne.TextWrapWord
+	summary.Hide()
+
+	cancelButton := widget.NewButton("Cancel", nil)
+	skipButton := widget.NewButton("Skip Step", nil)
+	applyButton := widget.NewButton("Apply", nil)
+	applyButton.Importance = widget.HighImportance
+	applyButton.Hide()
+
+	content := container.NewVBox(title, instructions, progress, pad, summary)
+	d := dialog.NewCustomWithoutButtons("Calibrate Delay", content, app.window)
+	d.SetButtons([]fyne.CanvasObject{cancelButton, skipButton, applyButton})
+
+	showStep := func() {
+		step := steps[stepIndex]
+		session.SetStep(step)
+		completeAt = time.Time{}
+		title.SetText(fmt.Sprintf("Step %d of %d: %s", stepIndex+1, len(steps), step.Title()))
+		instructions.SetText(step.Instructions())
+		progress.Max = float64(step.Target())
+		progress.SetValue(0)
+	}
+
+	finish := func() {
+		finished = true
+		results = session.Results()
+
+		lines := make([]string, 0, len(results))
+		for _, r := range results {
+			lines = append(lines, r.Summary())
+			app.logger.Log("🎯 %s", r.Summary())
+		}
+		if len(lines) == 0 {
+			lines = append(lines, "No clicks were recorded. Make sure protection is working and try again.")
+			applyButton.Disable()
+		}
+
+		title.SetText("Results")
+		instructions.SetText("Apply sets these delays for the buttons that were used; other buttons keep their settings.")
+		summary.SetText(strings.Join(lines, "\n\n"))
+		summary.Show()
+		progress.Hide()
+		pad.Hide()
+		skipButton.Hide()
+		applyButton.Show()
+		d.Resize(fyne.NewSize(420, d.MinSize().Height))
+	}
+
+	advance := func() {
+		stepIndex++
+		if stepIndex < len(steps) {
+			showStep()
+		} else {
+			finish()
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(calibrationPoll)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				fyne.Do(func() {
+					if finished {
+						return
+					}
+					step := steps[stepIndex]
+					n := session.Progress(step)
+					progress.SetValue(float64(min(n, step.Target())))
+					if n < step.Target() {
+						return
+					}
+					if completeAt.IsZero() {
+						completeAt = time.Now()
+					} else if time.Since(completeAt) >= calibrationLinger {
+						advance()
+					}
+				})
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	skipButton.OnTapped = advance
+	cancelButton.OnTapped = d.Hide
+	applyButton.OnTapped = func() {
+		app.applyCalibration(results)
+		applied = true
+		d.Hide()
+	}
+	d.SetOnClosed(func() {
+		close(done)
+		app.removeObserver("calibration")
+		switch {
+		case startedHere:
+			app.stopProtection()
+		case applied && app.isRunning:
+			// Restart so the new delays take effect
+			app.stopProtection()
+			app.startProtection()
+		}
+	})
+
+	showStep()
+	d.Resize(fyne.NewSize(420, d.MinSize().Height))
+	d.Show()
+}
+
+// applyCalibration sets the recommended delay of every calibrated button
+func (app *Application) applyCalibration(results []calibration.Result) {
+	for _, r := range results {
+		// Updates the label and saves the config through OnChanged
+		app.buttonDelaySliders[r.Button].SetValue(float64(r.DelayMs()))
+		app.logger.Log("🎯 %s button delay set to %d ms by calibration", r.Button, r.DelayMs())
+	}
+}
@@ -0,0 +1,156 @@
+package components
+
+import (
+	"fmt"
+	"image/color"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	chartHeight     = 120
+	chartLabelSize  = 10
+	chartTitleSize  = 13
+	chartMaxLabels  = 12 // Labels are thinned out beyond this many bars
+	chartBarSpacing = 2
+)
+
+// BarChart draws a titled bar chart with one label per bar
+type BarChart struct {
+	widget.BaseWidget
+	title  string
+	color  color.Color
+	labels []string
+	values []float64
+}
+
+// NewBarChart creates an empty bar chart
+func NewBarChart(title string, barColor color.Color) *BarChart {
+	b := &BarChart{title: title, color: barColor}
+	b.ExtendBaseWidget(b)
+	return b
+}
+
+// SetTitle changes the title shown above the chart
+func (b *BarChart) SetTitle(title string) {
+	b.title = title
+	b.Refresh()
+}
+
+// SetData replaces the bars; labels and values must have the same length
+func (b *BarChart) SetData(labels []string, values []float64) {
+	b.labels = labels
+	b.values = values
+	b.Refresh()
+}
+
+// CreateRenderer is a private method to Fyne which links this widget to its renderer
+func (b *BarChart) CreateRenderer() fyne.WidgetRenderer {
+	r := &barChartRenderer{chart: b}
+	r.rebuild()
+	return r
+}
+
+type barChartRenderer struct {
+	chart   *BarChart
+	title   *canvas.Text
+	maxText *canvas.Text
+	axis    *canvas.Line
+	bars    []*canvas.Rectangle
+	labels  []*canvas.Text
+	objects []fyne.CanvasObject
+}
+
+func (r *barChartRenderer) rebuild() {
+	r.title = canvas.NewText(r.chart.title, theme.Color(theme.ColorNameForeground))
+	r.title.TextStyle = fyne.TextStyle{Bold: true}
+	r.title.TextSize = chartTitleSize
+
+	maxValue := 0.0
+	for _, v := range r.chart.values {
+		maxValue = max(maxValue, v)
+	}
+	r.maxText = canvas.NewText(fmt.Sprintf("%.0f", maxValue), theme.Color(theme.ColorNamePlaceHolder))
+	r.maxText.TextSize = chartLabelSize
+	r.maxText.Alignment = fyne.TextAlignTrailing
+
+	r.axis = canvas.NewLine(theme.Color(theme.ColorNamePlaceHolder))
+	r.objects = []fyne.CanvasObject{r.title, r.maxText, r.axis}
+
+	step := (len(r.chart.labels) + chartMaxLabels - 1) / chartMaxLabels
+	r.bars = r.bars[:0]
+	r.labels = r.labels[:0]
+	for i := range r.chart.values {
+		bar := canvas.NewRectangle(r.chart.color)
+		r.bars = append(r.bars, bar)
+		r.objects = append(r.objects, bar)
+
+		text := ""
+		if i%max(step, 1) == 0 && i < len(r.chart.labels) {
+			text = r.chart.labels[i]
+		}
+		label := canvas.NewText(text, theme.Color(theme.ColorNamePlaceHolder))
+		label.TextSize = chartLabelSize
+		label.Alignment = fyne.TextAlignCenter
+		r.labels = append(r.labels, label)
+		r.objects = append(r.objects, label)
+	}
+}
+
+func (r *barChartRenderer) Layout(size fyne.Size) {
+	titleHeight := r.title.MinSize().Height
+	labelHeight := float32(chartLabelSize + 4)
+	r.title.Move(fyne.NewPos(0, 0))
+	r.title.Resize(fyne.NewSize(size.Width, titleHeight))
+
+	top := titleHeight + 4
+	bottom := size.Height - labelHeight
+	plotHeight := bottom - top
+	r.maxText.Move(fyne.NewPos(0, top))
+	r.maxText.Resize(fyne.NewSize(size.Width, labelHeight))
+	r.axis.Position1 = fyne.NewPos(0, bottom)
+	r.axis.Position2 = fyne.NewPos(size.Width, bottom)
+
+	maxValue := 0.0
+	for _, v := range r.chart.values {
+		maxValue = max(maxValue, v)
+	}
+	if len(r.bars) == 0 {
+		return
+	}
+
+	slot := size.Width / float32(len(r.bars))
+	for i, bar := range r.bars {
+		height := float32(0)
+		if maxValue > 0 {
+			height = plotHeight * float32(r.chart.values[i]/maxValue)
+		}
+		bar.Move(fyne.NewPos(float32(i)*slot+chartBarSpacing/2, bottom-height))
+		bar.Resize(fyne.NewSize(max(slot-chartBarSpacing, 1), height))
+
+		// Labels may be wider than their bar, center them on it
+		label := r.labels[i]
+		width := max(label.MinSize().Width, slot)
+		label.Move(fyne.NewPos(float32(i)*slot+slot/2-width/2, bottom+2))
+		label.Resize(fyne.NewSize(width, labelHeight))
+	}
+}
+
+func (r *barChartRenderer) MinSize() fyne.Size {
+	return fyne.NewSize(200, chartHeight)
+}
+
+func (r *barChartRenderer) Refresh() {
+	r.rebuild()
+	r.Layout(r.chart.Size())
+	canvas.Refresh(r.chart)
+}
+
+func (r *barChartRenderer) Objects() []fyne.CanvasObject {
+	return r.objects
+}
+
+func (r *barChartRenderer) Destroy() {}
@@ -0,0 +1,125 @@
+package components
+
+import (
+	"sync/atomic"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ClickPad is a target area for test clicks that lights up while a button
+// is held on it. Precise timings come from the hook; the pad gives the user
+// something to aim at and tells whether the pointer is over it.
+type ClickPad struct {
+	widget.BaseWidget
+	text    string
+	pressed int // Buttons currently held on the pad
+	hovered atomic.Bool
+
+	// OnMouseDown and OnMouseUp are called for presses and releases on the pad
+	OnMouseDown func(desktop.MouseButton)
+	OnMouseUp   func(desktop.MouseButton)
+}
+
+// NewClickPad creates a pad showing text in its center
+func NewClickPad(text string) *ClickPad {
+	p := &ClickPad{text: text}
+	p.ExtendBaseWidget(p)
+	return p
+}
+
+// SetText changes the text shown in the center of the pad
+func (p *ClickPad) SetText(text string) {
+	p.text = text
+	p.Refresh()
+}
+
+// Hovered reports whether the pointer is over the pad. It is safe to call
+// from any goroutine.
+func (p *ClickPad) Hovered() bool {
+	return p.hovered.Load()
+}
+
+// MouseDown is called when a button is pressed on the pad
+func (p *ClickPad) MouseDown(ev *desktop.MouseEvent) {
+	p.pressed++
+	p.Refresh()
+	if p.OnMouseDown != nil {
+		p.OnMouseDown(ev.Button)
+	}
+}
+
+// MouseUp is called when a button pressed on the pad is released
+func (p *ClickPad) MouseUp(ev *desktop.MouseEvent) {
+	p.pressed = max(p.pressed-1, 0)
+	p.Refresh()
+	if p.OnMouseUp != nil {
+		p.OnMouseUp(ev.Button)
+	}
+}
+
+// MouseIn is called when the pointer enters the pad
+func (p *ClickPad) MouseIn(*desktop.MouseEvent) {
+	p.hovered.Store(true)
+}
+
+// MouseOut is called when the pointer leaves the pad
+func (p *ClickPad) MouseOut() {
+	p.hovered.Store(false)
+}
+
+// MouseMoved is called when the pointer moves over the pad
+func (p *ClickPad) MouseMoved(*desktop.MouseEvent) {}
+
+// CreateRenderer is a private method to Fyne which links this widget to its renderer
+func (p *ClickPad) CreateRenderer() fyne.WidgetRenderer {
+	r := &clickPadRenderer{
+		pad:        p,
+		background: canvas.NewRectangle(theme.Color(theme.ColorNameInputBackground)),
+		text:       canvas.NewText(p.text, theme.Color(theme.ColorNameForeground)),
+	}
+	r.background.StrokeWidth = 2
+	r.background.CornerRadius = theme.InputRadiusSize()
+	r.text.Alignment = fyne.TextAlignCenter
+	r.Refresh()
+	return r
+}
+
+type clickPadRenderer struct {
+	pad        *ClickPad
+	background *canvas.Rectangle
+	text       *canvas.Text
+}
+
+func (r *clickPadRenderer) Layout(size fyne.Size) {
+	r.background.Resize(size)
+	textSize := r.text.MinSize()
+	r.text.Move(fyne.NewPos(0, (size.Height-textSize.Height)/2))
+	r.text.Resize(fyne.NewSize(size.Width, textSize.Height))
+}
+
+func (r *clickPadRenderer) MinSize() fyne.Size {
+	return fyne.NewSize(300, 150)
+}
+
+func (r *clickPadRenderer) Refresh() {
+	if r.pad.pressed > 0 {
+		r.background.FillColor = theme.Color(theme.ColorNamePrimary)
+	} else {
+		r.background.FillColor = theme.Color(theme.ColorNameInputBackground)
+	}
+	r.background.StrokeColor = theme.Color(theme.ColorNamePrimary)
+	r.text.Text = r.pad.text
+	r.text.Color = theme.Color(theme.ColorNameForeground)
+	r.background.Refresh()
+	r.text.Refresh()
+}
+
+func (r *clickPadRenderer) Objects() []fyne.CanvasObject {
+	return []fyne.CanvasObject{r.background, r.text}
+}
+
+func (r *clickPadRenderer) Destroy() {}
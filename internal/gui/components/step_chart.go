@@ -0,0 +1,187 @@
+package components
+
+import (
+	"fmt"
+	"image/color"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// StepPoint is a value that holds from X until the next point
+type StepPoint struct {
+	X float64
+	Y float64
+}
+
+// StepChart draws a value that changes in steps, such as a delay over time
+type StepChart struct {
+	widget.BaseWidget
+	title      string
+	color      color.Color
+	points     []StepPoint
+	end        float64 // X where the last value stops
+	startLabel string
+	endLabel   string
+	unit       string
+}
+
+// NewStepChart creates an empty step chart
+func NewStepChart(title, unit string, lineColor color.Color) *StepChart {
+	s := &StepChart{title: title, unit: unit, color: lineColor}
+	s.ExtendBaseWidget(s)
+	return s
+}
+
+// SetTitle changes the title shown above the chart
+func (s *StepChart) SetTitle(title string) {
+	s.title = title
+	s.Refresh()
+}
+
+// SetData replaces the points, which must be sorted by X. The last value is
+// drawn until end; the labels are shown below the start and the end.
+func (s *StepChart) SetData(points []StepPoint, end float64, startLabel, endLabel string) {
+	s.points = points
+	s.end = end
+	s.startLabel = startLabel
+	s.endLabel = endLabel
+	s.Refresh()
+}
+
+// CreateRenderer is a private method to Fyne which links this widget to its renderer
+func (s *StepChart) CreateRenderer() fyne.WidgetRenderer {
+	r := &stepChartRenderer{chart: s}
+	r.rebuild()
+	return r
+}
+
+type stepChartRenderer struct {
+	chart     *StepChart
+	title     *canvas.Text
+	maxText   *canvas.Text
+	minText   *canvas.Text
+	startText *canvas.Text
+	endText   *canvas.Text
+	axis      *canvas.Line
+	lines     []*canvas.Line
+	objects   []fyne.CanvasObject
+}
+
+func (r *stepChartRenderer) rebuild() {
+	r.title = canvas.NewText(r.chart.title, theme.Color(theme.ColorNameForeground))
+	r.title.TextStyle = fyne.TextStyle{Bold: true}
+	r.title.TextSize = chartTitleSize
+
+	low, high := r.bounds()
+	muted := theme.Color(theme.ColorNamePlaceHolder)
+	r.maxText = canvas.NewText(fmt.Sprintf("%.0f %s", high, r.chart.unit), muted)
+	r.minText = canvas.NewText(fmt.Sprintf("%.0f %s", low, r.chart.unit), muted)
+	r.startText = canvas.NewText(r.chart.startLabel, muted)
+	r.endText = canvas.NewText(r.chart.endLabel, muted)
+	for _, t := range []*canvas.Text{r.maxText, r.minText, r.startText, r.endText} {
+		t.TextSize = chartLabelSize
+	}
+	r.maxText.Alignment = fyne.TextAlignTrailing
+	r.minText.Alignment = fyne.TextAlignTrailing
+	r.endText.Alignment = fyne.TextAlignTrailing
+
+	r.axis = canvas.NewLine(muted)
+	r.objects = []fyne.CanvasObject{r.title, r.maxText, r.minText, r.startText, r.endText, r.axis}
+
+	// Each point needs a horizontal segment and a vertical one to the next
+	r.lines = r.lines[:0]
+	for i := 0; i < 2*len(r.chart.points)-1; i++ {
+		line := canvas.NewLine(r.chart.color)
+		line.StrokeWidth = 2
+		r.lines = append(r.lines, line)
+		r.objects = append(r.objects, line)
+	}
+}
+
+// bounds returns the value range shown, padded so flat lines stay visible
+func (r *stepChartRenderer) bounds() (float64, float64) {
+	if len(r.chart.points) == 0 {
+		return 0, 0
+	}
+	low, high := r.chart.points[0].Y, r.chart.points[0].Y
+	for _, p := range r.chart.points {
+		low = min(low, p.Y)
+		high = max(high, p.Y)
+	}
+	if high == low {
+		return max(low-10, 0), high + 10
+	}
+	return low, high
+}
+
+func (r *stepChartRenderer) Layout(size fyne.Size) {
+	titleHeight := r.title.MinSize().Height
+	labelHeight := float32(chartLabelSize + 4)
+	r.title.Move(fyne.NewPos(0, 0))
+	r.title.Resize(fyne.NewSize(size.Width, titleHeight))
+
+	top := titleHeight + 4
+	bottom := size.Height - labelHeight
+	r.maxText.Move(fyne.NewPos(0, top))
+	r.maxText.Resize(fyne.NewSize(size.Width, labelHeight))
+	r.minText.Move(fyne.NewPos(0, bottom-labelHeight))
+	r.minText.Resize(fyne.NewSize(size.Width, labelHeight))
+	r.startText.Move(fyne.NewPos(0, bottom+2))
+	r.startText.Resize(fyne.NewSize(size.Width/2, labelHeight))
+	r.endText.Move(fyne.NewPos(size.Width/2, bottom+2))
+	r.endText.Resize(fyne.NewSize(size.Width/2, labelHeight))
+	r.axis.Position1 = fyne.NewPos(0, bottom)
+	r.axis.Position2 = fyne.NewPos(size.Width, bottom)
+
+	points := r.chart.points
+	if len(points) == 0 {
+		return
+	}
+
+	low, high := r.bounds()
+	span := r.chart.end - points[0].X
+	x := func(v float64) float32 {
+		if span <= 0 {
+			return 0
+		}
+		return size.Width * float32((v-points[0].X)/span)
+	}
+	y := func(v float64) float32 {
+		return bottom - (bottom-top)*float32((v-low)/(high-low))
+	}
+
+	for i, p := range points {
+		next := r.chart.end
+		if i+1 < len(points) {
+			next = points[i+1].X
+		}
+		flat := r.lines[2*i]
+		flat.Position1 = fyne.NewPos(x(p.X), y(p.Y))
+		flat.Position2 = fyne.NewPos(x(next), y(p.Y))
+
+		if i+1 < len(points) {
+			rise := r.lines[2*i+1]
+			rise.Position1 = fyne.NewPos(x(next), y(p.Y))
+			rise.Position2 = fyne.NewPos(x(next), y(points[i+1].Y))
+		}
+	}
+}
+
+func (r *stepChartRenderer) MinSize() fyne.Size {
+	return fyne.NewSize(200, chartHeight)
+}
+
+func (r *stepChartRenderer) Refresh() {
+	r.rebuild()
+	r.Layout(r.chart.Size())
+	canvas.Refresh(r.chart)
+}
+
+func (r *stepChartRenderer) Objects() []fyne.CanvasObject {
+	return r.objects
+}
+
+func (r *stepChartRenderer) Destroy() {}
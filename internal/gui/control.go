@@ -0,0 +1,143 @@
+package gui
+
+import (
+	"fmt"
+	"time"
+
+	"fyne.io/fyne/v2"
+
+	"click-guardian/internal/control"
+	"click-guardian/internal/filter"
+	"click-guardian/internal/health"
+	"click-guardian/internal/hooks"
+	"click-guardian/internal/logger"
+	"click-guardian/internal/version"
+)
+
+// SetControlServer hands the control socket claimed at startup to the
+// application. It must be called before Run.
+func (app *Application) SetControlServer(server *control.Server) {
+	app.control = server
+}
+
+// startControlServer starts answering control requests. The application
+// keeps working without a control socket.
+func (app *Application) startControlServer() {
+	if app.control == nil {
+		return
+	}
+	if err := app.control.Serve(app); err != nil {
+		app.logger.Log("⚠️ Control socket unavailable: %v", err)
+		app.control.Close()
+		app.control = nil
+	}
+}
+
+// publishControlEvent forwards a hook event to clients tailing events
+func (app *Application) publishControlEvent(ev hooks.Event) {
+	if app.control != nil {
+		app.control.Publish(ev, logger.FormatEvent(ev))
+	}
+}
+
+// Control requests arrive on the goroutines of the control server. They run
+// on the main thread, like the buttons, so they never race with the UI.
+
+// Status implements control.Controller
+func (app *Application) Status() control.Status {
+	var status control.Status
+	fyne.DoAndWait(func() {
+		status = control.Status{
+			Running:       app.isRunning,
+			DelayMs:       app.config.DelayMs,
+			BlockedClicks: app.hook.GetBlockedCount(),
+			Version:       version.GetVersionString(),
+			Mode:          "gui",
+			SnoozedUntil:  app.snoozeEnd(),
+		}
+	})
+	return status
+}
+
+// StartProtection implements control.Controller
+func (app *Application) StartProtection() error {
+	if !app.hook.IsSupported() {
+		return fmt.Errorf("mouse hooking not supported on this platform")
+	}
+
+	var err error
+	fyne.DoAndWait(func() {
+		if app.isRunning {
+			return
+		}
+		app.logger.Log("Protection start requested via control socket")
+		app.startProtection()
+		if !app.isRunning {
+			err = fmt.Errorf("failed to start protection, see the activity log")
+		}
+	})
+	return err
+}
+
+// StopProtection implements control.Controller
+func (app *Application) StopProtection() error {
+	fyne.DoAndWait(func() {
+		if app.isRunning {
+			app.logger.Log("Protection stop requested via control socket")
+			app.stopProtection()
+		}
+	})
+	return nil
+}
+
+// SetDelay implements control.Controller. The new delay takes effect
+// immediately; active protection keeps its state and profile.
+func (app *Application) SetDelay(ms int) error {
+	fyne.DoAndWait(func() {
+		// Updates the label and saves the config through OnChanged
+		app.delaySlider.SetValue(float64(ms))
+		app.logger.Log("Delay set to %d ms via control socket", ms)
+
+		if !app.isRunning {
+			return
+		}
+		if app.profileWatcher != nil {
+			app.profileWatcher.Update(app.config)
+		} else {
+			app.hook.SetConfig(app.config.FilterConfig())
+		}
+	})
+	return nil
+}
+
+// Snooze implements control.Controller
+func (app *Application) Snooze(d time.Duration) error {
+	var err error
+	fyne.DoAndWait(func() {
+		app.logger.Log("Snooze requested via control socket")
+		err = app.snooze(d)
+	})
+	return err
+}
+
+// ShowWindow implements control.Controller
+func (app *Application) ShowWindow() error {
+	app.showFromTray()
+	fyne.Do(func() {
+		app.window.RequestFocus()
+	})
+	return nil
+}
+
+// Stats implements control.Controller
+func (app *Application) Stats() filter.Stats {
+	return app.hook.Stats()
+}
+
+// Health implements control.Controller
+func (app *Application) Health() ([]health.Report, error) {
+	if app.history == nil {
+		return nil, fmt.Errorf("lifetime statistics are unavailable")
+	}
+	return health.Evaluate(app.history.Days(health.WindowDays), time.Now()), nil
+}
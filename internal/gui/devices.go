@@ -0,0 +1,138 @@
+package gui
+
+import (
+	"fmt"
+	"sort"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"click-guardian/internal/config"
+	"click-guardian/internal/hooks"
+)
+
+// setupDevicesSection builds the per-device settings, one row for every
+// attached or configured mouse
+func (app *Application) setupDevicesSection() fyne.CanvasObject {
+	app.deviceList = container.NewVBox()
+	app.refreshDeviceList()
+
+	refreshButton := widget.NewButton("Refresh", app.refreshDeviceList)
+
+	return widget.NewAccordion(widget.NewAccordionItem("Devices", container.NewVBox(
+		app.deviceList,
+		container.NewHBox(refreshButton),
+	)))
+}
+
+// refreshDeviceList lists the attached devices followed by configured ones
+// that are not connected. It must run on the main thread.
+func (app *Application) refreshDeviceList() {
+	app.deviceList.RemoveAll()
+	app.deviceControls = nil
+
+	if !hooks.PerDeviceFiltering {
+		hint := widget.NewLabel("This system cannot tell which mouse made a click, so every mouse uses the button settings. Device settings only apply on Linux.")
+		hint.Wrapping = fyne.TextWrapWord
+		app.deviceList.Add(hint)
+	}
+
+	attached, err := app.hook.Devices()
+	if err != nil {
+		hint := widget.NewLabel(fmt.Sprintf("Devices unavailable: %v", err))
+		hint.Wrapping = fyne.TextWrapWord
+		app.deviceList.Add(hint)
+	}
+
+	connected := make(map[string]bool)
+	var devices []hooks.Device
+	for _, dev := range attached {
+		// Identical devices share an ID and therefore one row
+		if !connected[dev.ID] {
+			connected[dev.ID] = true
+			devices = append(devices, dev)
+		}
+	}
+	var missing []hooks.Device
+	for id, settings := range app.config.Devices {
+		if !connected[id] {
+			missing = append(missing, hooks.Device{ID: id, Name: settings.Name})
+		}
+	}
+	sort.Slice(missing, func(i, j int) bool { return missing[i].ID < missing[j].ID })
+
+	for _, dev := range devices {
+		app.deviceList.Add(app.deviceRow(dev, true))
+	}
+	for _, dev := range missing {
+		app.deviceList.Add(app.deviceRow(dev, false))
+	}
+	if len(devices) == 0 && len(missing) == 0 && err == nil {
+		app.deviceList.Add(widget.NewLabel("No mouse found."))
+	}
+}
+
+// deviceRow shows the enable flag and delay of a device
+func (app *Application) deviceRow(dev hooks.Device, connected bool) fyne.CanvasObject {
+	settings, ok := app.config.Devices[dev.ID]
+	if !ok {
+		settings = config.DeviceSettings{Name: dev.Name, Enabled: true}
+	}
+
+	title := dev.String()
+	if !connected {
+		title += " (not connected)"
+	}
+	save := func() {
+		settings.Name = dev.Name
+		app.config.SetDeviceSettings(dev.ID, settings)
+		if err := app.config.Save(); err != nil {
+			app.logger.Log("⚠️ Failed to save device setting: %v", err)
+		}
+	}
+
+	check := widget.NewCheck(title, nil)
+	check.SetChecked(settings.Enabled)
+	check.OnChanged = func(checked bool) {
+		settings.Enabled = checked
+		save()
+	}
+
+	label := widget.NewLabel("")
+	showDelay := func() {
+		if settings.DelayMs > 0 {
+			label.SetText(fmt.Sprintf("%d ms", settings.DelayMs))
+		} else {
+			label.SetText("configured delays")
+		}
+	}
+	showDelay()
+	slider := widget.NewSlider(0, config.MaxDelayMs)
+	slider.Step = 5
+	slider.SetValue(float64(settings.DelayMs))
+	slider.OnChanged = func(value float64) {
+		settings.DelayMs = int(value)
+		if settings.DelayMs > 0 && settings.DelayMs < config.MinDelayMs {
+			settings.DelayMs = config.MinDelayMs
+		}
+		showDelay()
+		save()
+	}
+
+	row := container.NewVBox(check, container.NewBorder(nil, nil, nil, label, slider))
+	if !hooks.PerDeviceFiltering {
+		check.Disable()
+		slider.Disable()
+		return row
+	}
+
+	// Like the other settings, devices are fixed while protection runs
+	if app.isRunning {
+		check.Disable()
+		slider.Disable()
+	}
+	app.deviceControls = append(app.deviceControls, check, slider)
+
+	return row
+}
@@ -0,0 +1,72 @@
+package gui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+
+	"click-guardian/internal/health"
+)
+
+// monitorHealth scores the buttons after every statistics save and notifies
+// the user when one drops below the configured threshold
+func (app *Application) monitorHealth() {
+	monitor := health.NewMonitor(app.config.HealthWarningScore)
+	ticker := time.NewTicker(historyInterval)
+	defer ticker.Stop()
+
+	for {
+		reports := health.Evaluate(app.history.Days(health.WindowDays), time.Now())
+		for _, report := range monitor.Check(reports) {
+			app.logger.Log("⚠️ %s button health is %d/100: %s", report.Button, report.Score, report.Recommendation())
+			app.app.SendNotification(fyne.NewNotification(
+				"Click Guardian - Mouse Health",
+				fmt.Sprintf("%s button health dropped to %d/100. %s.", report.Button, report.Score, report.Recommendation()),
+			))
+		}
+		fyne.Do(func() {
+			app.healthReports = reports
+		})
+		app.updateTrayTooltip()
+
+		select {
+		case <-ticker.C:
+		case <-app.shutdownChan:
+			return
+		}
+	}
+}
+
+// healthTooltip lists the scored buttons for the tray tooltip
+func healthTooltip(reports []health.Report) string {
+	var scores []string
+	for _, r := range reports {
+		if r.Score >= 0 {
+			scores = append(scores, fmt.Sprintf("%s %d", r.Button, r.Score))
+		}
+	}
+	if len(scores) == 0 {
+		return ""
+	}
+	return "\nHealth: " + strings.Join(scores, ", ")
+}
+
+// healthSummary describes the health of every button for the statistics tab
+func healthSummary(reports []health.Report) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Mouse health over the last %d days:", health.WindowDays)
+	for _, r := range reports {
+		if r.Score < 0 {
+			fmt.Fprintf(&sb, "\n%s: not scored yet (%d of %d clicks)", r.Button, r.Clicks, health.MinClicks)
+			continue
+		}
+		fmt.Fprintf(&sb, "\n%s: %d/100 (%s, %.1f%% bounces", r.Button, r.Score, r.Level, 100*r.BounceRate)
+		if r.Trend > 0 {
+			fmt.Fprintf(&sb, ", up %.1f points this week", 100*r.Trend)
+		}
+		fmt.Fprintf(&sb, ") - %s", r.Recommendation())
+	}
+	return sb.String()
+}
@@ -0,0 +1,28 @@
+package gui
+
+import (
+	"time"
+
+	"click-guardian/internal/history"
+)
+
+// historyInterval is how often the session statistics are saved
+const historyInterval = time.Minute
+
+// startHistory opens the lifetime statistics and keeps adding the session to them
+func (app *Application) startHistory() {
+	path, err := history.DefaultPath()
+	if err == nil {
+		app.history, err = history.Open(path, app.config.StatsRetentionDays)
+	}
+	if err != nil {
+		app.logger.Log("⚠️ Lifetime statistics unavailable: %v", err)
+		return
+	}
+
+	app.historyCollector = history.NewCollector(app.history, app.hook)
+	go app.historyCollector.Run(historyInterval, app.shutdownChan, func(err error) {
+		app.logger.Log("⚠️ Failed to save statistics: %v", err)
+	})
+	go app.monitorHealth()
+}
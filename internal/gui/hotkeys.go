@@ -0,0 +1,99 @@
+package gui
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"click-guardian/internal/hotkey"
+)
+
+// bypassNone is the bypass option that turns the bypass off
+const bypassNone = "None"
+
+// setupHotkeySection builds the pause hotkey and bypass modifier settings.
+// Both take effect immediately, protection does not have to be restarted.
+func (app *Application) setupHotkeySection() fyne.CanvasObject {
+	app.hotkeyEntry = widget.NewEntry()
+	app.hotkeyEntry.SetPlaceHolder("e.g. Ctrl+Alt+P, empty for none")
+	app.hotkeyEntry.SetText(app.config.PauseHotkey)
+	app.hotkeyEntry.Validator = func(s string) error {
+		_, err := hotkey.Parse(s)
+		return err
+	}
+	app.hotkeyEntry.OnSubmitted = app.setPauseHotkey
+	setButton := widget.NewButton("Set", func() {
+		app.setPauseHotkey(app.hotkeyEntry.Text)
+	})
+
+	options := []string{bypassNone}
+	for _, mod := range []hotkey.Modifier{hotkey.ModCtrl, hotkey.ModAlt, hotkey.ModShift, hotkey.ModSuper} {
+		options = append(options, mod.String())
+	}
+	app.bypassSelect = widget.NewSelect(options, nil)
+	if bypass, _ := hotkey.ParseModifier(app.config.BypassModifier); bypass != 0 {
+		app.bypassSelect.SetSelected(bypass.String())
+	} else {
+		app.bypassSelect.SetSelected(bypassNone)
+	}
+	app.bypassSelect.OnChanged = func(selected string) {
+		if selected == bypassNone {
+			selected = ""
+		}
+		app.config.BypassModifier = selected
+		if err := app.config.Save(); err != nil {
+			app.logger.Log("⚠️ Failed to save bypass modifier setting: %v", err)
+		}
+		app.restartHotkeys()
+	}
+
+	return container.NewVBox(
+		widget.NewLabel("Pause/resume hotkey:"),
+		container.NewBorder(nil, nil, nil, setButton, app.hotkeyEntry),
+		container.NewBorder(nil, nil, widget.NewLabel("Hold to let clicks through:"), nil, app.bypassSelect),
+	)
+}
+
+// setPauseHotkey saves a valid hotkey and starts listening for it
+func (app *Application) setPauseHotkey(text string) {
+	key, err := hotkey.Parse(text)
+	if err != nil {
+		app.logger.Log("⚠️ Invalid hotkey: %v", err)
+		return
+	}
+	app.config.PauseHotkey = key.String()
+	app.hotkeyEntry.SetText(app.config.PauseHotkey)
+	if err := app.config.Save(); err != nil {
+		app.logger.Log("⚠️ Failed to save hotkey setting: %v", err)
+	}
+	app.restartHotkeys()
+}
+
+// startHotkeys watches for the pause hotkey and the bypass modifier. They
+// are active whether or not protection is running.
+func (app *Application) startHotkeys() {
+	cfg := app.config.HotkeyConfig()
+	if !cfg.Enabled() {
+		return
+	}
+	if !app.hotkeys.IsSupported() {
+		app.logger.Log("⚠️ Global hotkeys not supported on this platform")
+		return
+	}
+	if err := app.hotkeys.Start(cfg, app.hookEvents); err != nil {
+		app.logger.Log("⚠️ Global hotkeys unavailable: %v", err)
+		return
+	}
+	if cfg.Toggle.Key != "" {
+		app.logger.Log("⌨️ Press %s to pause or resume protection", cfg.Toggle)
+	}
+	if cfg.Bypass != 0 {
+		app.logger.Log("⌨️ Hold %s to let clicks through unfiltered", cfg.Bypass)
+	}
+}
+
+// restartHotkeys applies changed hotkey settings
+func (app *Application) restartHotkeys() {
+	app.hotkeys.Stop()
+	app.startHotkeys()
+}
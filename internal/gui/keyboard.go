@@ -0,0 +1,95 @@
+package gui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"click-guardian/internal/config"
+)
+
+// setupKeyboardSection builds the keyboard chatter toggle and threshold.
+// The toggle takes effect immediately, the threshold on the next start.
+func (app *Application) setupKeyboardSection() fyne.CanvasObject {
+	app.keyThresholdSlider = widget.NewSlider(config.MinKeyThresholdMs, config.MaxKeyThresholdMs)
+	app.keyThresholdSlider.SetValue(float64(app.config.KeyThresholdMs))
+	app.keyThresholdSlider.Step = 5
+	app.keyThresholdLabel = widget.NewLabel(fmt.Sprintf("%d ms", app.config.KeyThresholdMs))
+	app.keyThresholdLabel.Alignment = fyne.TextAlignCenter
+	app.keyThresholdSlider.OnChanged = func(value float64) {
+		app.keyThresholdLabel.SetText(fmt.Sprintf("%.0f ms", value))
+		app.config.KeyThresholdMs = int(value)
+		if err := app.config.Save(); err != nil {
+			app.logger.Log("⚠️ Failed to save key threshold setting: %v", err)
+		}
+	}
+
+	app.keyboardCheck = widget.NewCheck("Filter keyboard key chatter", func(checked bool) {
+		app.config.KeyboardEnabled = checked
+		if checked {
+			app.keyCounterText.Show()
+		} else {
+			app.keyCounterText.Hide()
+		}
+		if err := app.config.Save(); err != nil {
+			app.logger.Log("⚠️ Failed to save keyboard filter setting: %v", err)
+		}
+		if app.isRunning {
+			if checked {
+				app.startKeyboardFilter()
+			} else {
+				app.stopKeyboardFilter()
+			}
+		}
+	})
+	app.keyboardCheck.SetChecked(app.config.KeyboardEnabled)
+
+	return container.NewVBox(
+		app.keyboardCheck,
+		widget.NewLabel("Key chatter threshold (ms):"),
+		app.keyThresholdSlider,
+		container.NewCenter(app.keyThresholdLabel),
+	)
+}
+
+// startKeyboardFilter grabs the keyboards; mouse protection keeps running
+// when that fails
+func (app *Application) startKeyboardFilter() {
+	if !app.keyboard.IsSupported() {
+		app.logger.Log("⚠️ Keyboard chatter filter not supported on this platform")
+		return
+	}
+	if err := app.keyboard.Start(app.config.KeyFilterConfig(), app.hookEvents); err != nil {
+		app.logger.Log("⚠️ Keyboard chatter filter unavailable: %v", err)
+		return
+	}
+	app.logger.Log("⌨️ Filtering keyboard chatter with a %d ms threshold", app.config.KeyThresholdMs)
+}
+
+// stopKeyboardFilter releases the keyboards
+func (app *Application) stopKeyboardFilter() {
+	app.keyboard.Stop()
+}
+
+// keyStatsBreakdown describes the blocked key presses for the status tooltip
+func (app *Application) keyStatsBreakdown() string {
+	stats := app.keyboard.Stats()
+	if stats.Allowed == 0 && len(stats.Blocked) == 0 {
+		return ""
+	}
+
+	keys := make([]string, 0, len(stats.Blocked))
+	for name, n := range stats.Blocked {
+		keys = append(keys, fmt.Sprintf("%s %d", name, n))
+	}
+	sort.Strings(keys)
+	s := fmt.Sprintf("\nKeyboard: %d presses, %d chatter blocked", stats.Allowed+stats.TotalBlocked(), stats.TotalBlocked())
+	if len(keys) > 0 {
+		s += "\n  " + strings.Join(keys, ", ")
+	}
+	return s
+}
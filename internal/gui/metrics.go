@@ -0,0 +1,20 @@
+package gui
+
+import (
+	"click-guardian/internal/metrics"
+)
+
+// startMetricsServer starts the Prometheus exporter when it is enabled
+func (app *Application) startMetricsServer() {
+	if !app.config.MetricsEnabled {
+		return
+	}
+
+	server, err := metrics.Listen(app.config.MetricsAddress, app.hook)
+	if err != nil {
+		app.logger.Log("⚠️ Metrics exporter unavailable: %v", err)
+		return
+	}
+	app.metrics = server
+	app.logger.Log("📈 Metrics available at http://%s/metrics", server.Address())
+}
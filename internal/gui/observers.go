@@ -0,0 +1,52 @@
+package gui
+
+import (
+	"sort"
+
+	"click-guardian/internal/filter"
+)
+
+// addObserver lets a named consumer see every event the hook processes.
+// The hook has a single observer slot, so all consumers share it.
+func (app *Application) addObserver(name string, observer filter.Observer) {
+	app.observerMu.Lock()
+	defer app.observerMu.Unlock()
+	if app.observers == nil {
+		app.observers = make(map[string]filter.Observer)
+	}
+	app.observers[name] = observer
+	app.installObservers()
+}
+
+// removeObserver stops forwarding events to a consumer
+func (app *Application) removeObserver(name string) {
+	app.observerMu.Lock()
+	defer app.observerMu.Unlock()
+	delete(app.observers, name)
+	app.installObservers()
+}
+
+// installObservers hands the hook a fixed list so events are forwarded
+// without taking a lock. Callers hold observerMu.
+func (app *Application) installObservers() {
+	if len(app.observers) == 0 {
+		app.hook.SetObserver(nil)
+		return
+	}
+
+	names := make([]string, 0, len(app.observers))
+	for name := range app.observers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	list := make([]filter.Observer, len(names))
+	for i, name := range names {
+		list[i] = app.observers[name]
+	}
+
+	app.hook.SetObserver(func(ev filter.Event, d filter.Decision) {
+		for _, observer := range list {
+			observer(ev, d)
+		}
+	})
+}
@@ -0,0 +1,169 @@
+package gui
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"click-guardian/internal/config"
+	"click-guardian/internal/profiles"
+)
+
+// setupProfilesSection builds the list of application profiles with buttons
+// to add, edit and remove them
+func (app *Application) setupProfilesSection() fyne.CanvasObject {
+	app.profileList = container.NewVBox()
+	app.refreshProfileList()
+
+	addButton := widget.NewButton("Add Profile...", func() {
+		app.showProfileDialog(-1)
+	})
+
+	return widget.NewAccordion(widget.NewAccordionItem("Application profiles", container.NewVBox(
+		app.profileList,
+		container.NewHBox(addButton),
+	)))
+}
+
+// refreshProfileList shows one row per configured profile
+func (app *Application) refreshProfileList() {
+	app.profileList.RemoveAll()
+	if len(app.config.Profiles) == 0 {
+		hint := widget.NewLabel("No profiles. Every application uses the settings above.")
+		hint.Wrapping = fyne.TextWrapWord
+		app.profileList.Add(hint)
+		return
+	}
+
+	for i, p := range app.config.Profiles {
+		label := widget.NewLabel(fmt.Sprintf("%s: %s (%s)", p.Name, p.Match, p.Summary()))
+		label.Truncation = fyne.TextTruncateEllipsis
+		editButton := widget.NewButton("Edit", func() {
+			app.showProfileDialog(i)
+		})
+		removeButton := widget.NewButton("Remove", func() {
+			app.config.Profiles = append(app.config.Profiles[:i:i], app.config.Profiles[i+1:]...)
+			app.saveProfiles()
+		})
+		app.profileList.Add(container.NewBorder(nil, nil, nil,
+			container.NewHBox(editButton, removeButton), label))
+	}
+}
+
+// showProfileDialog edits the profile at index, or adds a new one when
+// index is negative
+func (app *Application) showProfileDialog(index int) {
+	profile := config.Profile{Enabled: true}
+	title := "Add Profile"
+	if index >= 0 {
+		profile = app.config.Profiles[index]
+		title = "Edit Profile"
+	}
+
+	nameEntry := widget.NewEntry()
+	nameEntry.SetText(profile.Name)
+	nameEntry.SetPlaceHolder("Games")
+	matchEntry := widget.NewEntry()
+	matchEntry.SetText(profile.Match)
+	matchEntry.SetPlaceHolder("game.exe or window class")
+	matchEntry.Validator = func(s string) error {
+		if strings.TrimSpace(s) == "" {
+			return fmt.Errorf("enter an executable name or window class")
+		}
+		return nil
+	}
+
+	delayLabel := widget.NewLabel("")
+	delaySlider := widget.NewSlider(0, config.MaxDelayMs)
+	delaySlider.Step = 5
+	delaySlider.OnChanged = func(value float64) {
+		if value == 0 {
+			delayLabel.SetText("configured delays")
+		} else {
+			delayLabel.SetText(fmt.Sprintf("%.0f ms", max(value, config.MinDelayMs)))
+		}
+	}
+	delaySlider.SetValue(float64(profile.DelayMs))
+	delaySlider.OnChanged(delaySlider.Value)
+
+	enabledCheck := widget.NewCheck("Protect this application", func(checked bool) {
+		if checked {
+			delaySlider.Enable()
+		} else {
+			delaySlider.Disable()
+		}
+	})
+	enabledCheck.SetChecked(profile.Enabled)
+	enabledCheck.OnChanged(profile.Enabled)
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Name", nameEntry),
+		widget.NewFormItem("Application", matchEntry),
+		widget.NewFormItem("", enabledCheck),
+		widget.NewFormItem("Delay", container.NewBorder(nil, nil, nil, delayLabel, delaySlider)),
+	}
+	d := dialog.NewForm(title, "Save", "Cancel", items, func(save bool) {
+		if !save {
+			return
+		}
+		profile.Match = strings.TrimSpace(matchEntry.Text)
+		profile.Name = strings.TrimSpace(nameEntry.Text)
+		if profile.Name == "" {
+			profile.Name = profile.Match
+		}
+		profile.Enabled = enabledCheck.Checked
+		profile.DelayMs = 0
+		if delaySlider.Value > 0 {
+			profile.DelayMs = max(int(delaySlider.Value), config.MinDelayMs)
+		}
+
+		if index >= 0 {
+			app.config.Profiles[index] = profile
+		} else {
+			app.config.Profiles = append(app.config.Profiles, profile)
+		}
+		app.saveProfiles()
+	}, app.window)
+	d.Resize(fyne.NewSize(420, d.MinSize().Height))
+	d.Show()
+}
+
+// saveProfiles stores edited profiles and applies them to active protection
+func (app *Application) saveProfiles() {
+	app.refreshProfileList()
+	if err := app.config.Save(); err != nil {
+		app.logger.Log("⚠️ Failed to save application profiles: %v", err)
+	}
+	if app.isRunning {
+		app.stopProfileWatcher()
+		app.startProfileWatcher()
+	}
+}
+
+// startProfileWatcher switches the hook settings with the foreground
+// application while protection runs
+func (app *Application) startProfileWatcher() {
+	app.profileWatcher = profiles.Start(app.config, app.hook, func(s profiles.Switch) {
+		app.logger.Log("%s", s.Describe())
+		fyne.Do(func() {
+			app.activeProfile = ""
+			if s.Profile != nil {
+				app.activeProfile = s.Profile.Name
+			}
+		})
+		app.updateTrayTooltip()
+	})
+}
+
+// stopProfileWatcher restores the configured settings in the hook
+func (app *Application) stopProfileWatcher() {
+	app.profileWatcher.Stop()
+	app.profileWatcher = nil
+	fyne.Do(func() {
+		app.activeProfile = ""
+	})
+}
@@ -0,0 +1,132 @@
+package gui
+
+import (
+	"fmt"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+	"fyne.io/systray"
+)
+
+// snoozeOptions are the pauses offered in the window and the tray menu
+var snoozeOptions = []time.Duration{5 * time.Minute, 15 * time.Minute, time.Hour}
+
+// snoozeLabel names a pause, e.g. "15 min" or "1 hour"
+func snoozeLabel(d time.Duration) string {
+	if d == time.Hour {
+		return "1 hour"
+	}
+	return fmt.Sprintf("%d min", int(d/time.Minute))
+}
+
+// setupSnoozeRow builds the buttons that pause protection for a while
+func (app *Application) setupSnoozeRow() fyne.CanvasObject {
+	row := container.NewHBox(widget.NewLabel("Pause for:"))
+	for _, d := range snoozeOptions {
+		row.Add(widget.NewButton(snoozeLabel(d), func() {
+			if err := app.snooze(d); err != nil {
+				app.logger.Log("⚠️ Cannot snooze: %v", err)
+			}
+		}))
+	}
+	return container.NewCenter(row)
+}
+
+// addTraySnoozeMenu adds the tray submenu that pauses protection for a while
+func (app *Application) addTraySnoozeMenu() {
+	menu := systray.AddMenuItem("Pause Protection", "Stop protection and start it again automatically")
+	for _, d := range snoozeOptions {
+		item := menu.AddSubMenuItem("For "+snoozeLabel(d), "")
+		go func() {
+			for {
+				select {
+				case <-item.ClickedCh:
+					fyne.Do(func() {
+						if err := app.snooze(d); err != nil {
+							app.logger.Log("⚠️ Cannot snooze: %v", err)
+						}
+					})
+				case <-app.shutdownChan:
+					return
+				}
+			}
+		}()
+	}
+}
+
+// snooze stops protection for d and starts it again once it has passed.
+// Snoozing again replaces the pending snooze. It must run on the main
+// thread.
+func (app *Application) snooze(d time.Duration) error {
+	if !app.isRunning && app.snoozeEnd().IsZero() {
+		return fmt.Errorf("protection is not active")
+	}
+
+	app.stopProtection()
+
+	app.snoozeMu.Lock()
+	app.snoozedUntil = time.Now().Add(d)
+	app.snoozeGen++
+	gen := app.snoozeGen
+	app.snoozeTimer = time.AfterFunc(d, func() {
+		fyne.Do(func() { app.resumeFromSnooze(gen) })
+	})
+	until := app.snoozedUntil
+	app.snoozeMu.Unlock()
+
+	app.logger.Log("😴 Protection snoozed for %s, resuming at %s", snoozeLabel(d), until.Format("15:04:05"))
+	app.toggleButton.SetText("Resume Protection")
+	app.updateTrayTooltip()
+	return nil
+}
+
+// resumeFromSnooze starts protection when the snooze gen is still pending.
+// A snooze replaced or cancelled after its timer fired does not resume
+// anything.
+func (app *Application) resumeFromSnooze(gen uint64) {
+	app.snoozeMu.Lock()
+	current := app.snoozeGen == gen
+	app.snoozeMu.Unlock()
+	if !current {
+		return
+	}
+	app.logger.Log("⏰ Snooze is over, resuming protection")
+	app.startProtection()
+}
+
+// cancelSnooze forgets a pending snooze and reports whether there was one;
+// protection stays as it is
+func (app *Application) cancelSnooze() bool {
+	app.snoozeMu.Lock()
+	defer app.snoozeMu.Unlock()
+
+	if app.snoozeTimer == nil {
+		return false
+	}
+	app.snoozeTimer.Stop()
+	app.snoozeTimer = nil
+	app.snoozeGen++
+	app.snoozedUntil = time.Time{}
+	return true
+}
+
+// snoozeEnd returns when snoozed protection starts again, zero unless snoozed
+func (app *Application) snoozeEnd() time.Time {
+	app.snoozeMu.Lock()
+	defer app.snoozeMu.Unlock()
+	return app.snoozedUntil
+}
+
+// snoozeStatus describes the remaining pause, e.g. "resumes in 12 min
+// (15:04)", or returns "" unless snoozed
+func (app *Application) snoozeStatus() string {
+	end := app.snoozeEnd()
+	if end.IsZero() {
+		return ""
+	}
+	// Rounded up, so the last minute still shows as one
+	minutes := int((time.Until(end) + time.Minute - 1) / time.Minute)
+	return fmt.Sprintf("resumes in %d min (%s)", max(minutes, 1), end.Format("15:04"))
+}
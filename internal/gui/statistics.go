@@ -0,0 +1,229 @@
+package gui
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"click-guardian/internal/filter"
+	"click-guardian/internal/gui/components"
+	"click-guardian/internal/health"
+	"click-guardian/internal/history"
+)
+
+// Ranges offered by the statistics tab, in days of history
+var statsRanges = []struct {
+	name string
+	days int
+}{
+	{"Today", 1},
+	{"Last 7 days", 7},
+	{"Last 30 days", 30},
+	{"All time", 0},
+}
+
+// delaySample is an adaptive delay change of the current session
+type delaySample struct {
+	time  time.Time
+	delay time.Duration
+}
+
+// statisticsView holds the widgets of the statistics tab
+type statisticsView struct {
+	rangeSelect   *widget.Select
+	buttonSelect  *widget.Select
+	summary       *widget.Label
+	health        *widget.Label
+	hourlyChart   *components.BarChart
+	intervalChart *components.BarChart
+	holdChart     *components.BarChart
+	delayChart    *components.StepChart
+
+	mu            sync.Mutex
+	sessionStart  time.Time
+	delayTimeline map[filter.Button][]delaySample
+}
+
+// setupStatisticsTab builds the charts shown in the Statistics tab
+func (app *Application) setupStatisticsTab() fyne.CanvasObject {
+	v := &statisticsView{
+		summary:       widget.NewLabel(""),
+		health:        widget.NewLabel(""),
+		hourlyChart:   components.NewBarChart("Blocked clicks per hour", color.RGBA{R: 220, G: 53, B: 69, A: 255}),
+		intervalChart: components.NewBarChart("", color.RGBA{R: 0, G: 123, B: 255, A: 255}),
+		holdChart:     components.NewBarChart("", color.RGBA{R: 40, G: 167, B: 69, A: 255}),
+		delayChart:    components.NewStepChart("", "ms", color.RGBA{R: 255, G: 193, B: 7, A: 255}),
+		delayTimeline: make(map[filter.Button][]delaySample),
+	}
+	v.summary.Wrapping = fyne.TextWrapWord
+	app.statsView = v
+
+	rangeNames := make([]string, len(statsRanges))
+	for i, r := range statsRanges {
+		rangeNames[i] = r.name
+	}
+	v.rangeSelect = widget.NewSelect(rangeNames, func(string) { app.refreshStatistics() })
+
+	buttonNames := make([]string, 0, len(filter.Buttons()))
+	for _, b := range filter.Buttons() {
+		buttonNames = append(buttonNames, b.String())
+	}
+	v.buttonSelect = widget.NewSelect(buttonNames, func(string) { app.refreshStatistics() })
+
+	refreshButton := widget.NewButton("Refresh", app.refreshStatistics)
+
+	// Setting the selection triggers a refresh, do it once everything exists
+	v.rangeSelect.SetSelected(statsRanges[1].name)
+	v.buttonSelect.SetSelected(filter.ButtonLeft.String())
+
+	controls := container.NewHBox(v.rangeSelect, v.buttonSelect, refreshButton)
+	return container.NewVScroll(container.NewVBox(
+		controls,
+		v.summary,
+		v.health,
+		v.hourlyChart,
+		v.intervalChart,
+		v.holdChart,
+		v.delayChart,
+	))
+}
+
+// resetDelayTimeline starts a new adaptive delay timeline at the base delays
+func (app *Application) resetDelayTimeline(cfg filter.Config) {
+	v := app.statsView
+	if v == nil {
+		return
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.sessionStart = time.Now()
+	v.delayTimeline = make(map[filter.Button][]delaySample)
+	for _, b := range filter.Buttons() {
+		v.delayTimeline[b] = []delaySample{{time: v.sessionStart, delay: cfg.DelayFor(b)}}
+	}
+}
+
+// recordDelayChange adds an adaptive delay change to the timeline
+func (app *Application) recordDelayChange(button filter.Button, at time.Time, delay time.Duration) {
+	v := app.statsView
+	if v == nil {
+		return
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.delayTimeline[button] = append(v.delayTimeline[button], delaySample{time: at, delay: delay})
+}
+
+// refreshStatistics saves the session statistics and redraws the charts
+func (app *Application) refreshStatistics() {
+	v := app.statsView
+	if v == nil || v.rangeSelect.Selected == "" || v.buttonSelect.Selected == "" {
+		return
+	}
+
+	button, _ := filter.ParseButton(v.buttonSelect.Selected)
+	days := statsRanges[v.rangeSelect.SelectedIndex()].days
+
+	var recorded []history.Day
+	if app.history != nil {
+		// Include the clicks since the last periodic save
+		if err := app.historyCollector.Flush(); err != nil {
+			app.logger.Log("⚠️ Failed to save statistics: %v", err)
+		}
+		recorded = app.history.Days(days)
+		v.health.SetText(healthSummary(health.Evaluate(app.history.Days(health.WindowDays), time.Now())))
+	}
+
+	var hourly [24]int
+	var intervals, holds filter.Histogram
+	allowed, blocked := 0, 0
+	key := strings.ToLower(button.String())
+	for _, day := range recorded {
+		for hour, n := range day.BlockedByHour {
+			hourly[hour] += n
+		}
+		if b, ok := day.Buttons[key]; ok {
+			intervals.Add(b.Intervals)
+			holds.Add(b.Holds)
+			allowed += b.Allowed
+			blocked += b.BlockedClicks()
+		}
+	}
+
+	hourLabels := make([]string, 24)
+	hourValues := make([]float64, 24)
+	for hour := range hourly {
+		hourLabels[hour] = fmt.Sprintf("%d", hour)
+		hourValues[hour] = float64(hourly[hour])
+	}
+	v.hourlyChart.SetData(hourLabels, hourValues)
+
+	bucketLabels := histogramLabels()
+	v.intervalChart.SetTitle(fmt.Sprintf("%s press intervals (ms)", button))
+	v.intervalChart.SetData(bucketLabels, histogramValues(intervals))
+	v.holdChart.SetTitle(fmt.Sprintf("%s hold durations (ms)", button))
+	v.holdChart.SetData(bucketLabels, histogramValues(holds))
+
+	summary := fmt.Sprintf("%s button: %d clicks allowed, %d blocked", button, allowed, blocked)
+	if allowed+blocked > 0 {
+		summary += fmt.Sprintf(" (%.1f%% blocked)", 100*float64(blocked)/float64(allowed+blocked))
+	}
+	if len(recorded) == 0 {
+		summary = "No statistics recorded for this range yet"
+	}
+	v.summary.SetText(summary)
+
+	app.refreshDelayChart(button)
+}
+
+// refreshDelayChart draws the adaptive delay of a button in this session
+func (app *Application) refreshDelayChart(button filter.Button) {
+	v := app.statsView
+	v.delayChart.SetTitle(fmt.Sprintf("%s adaptive delay this session", button))
+
+	v.mu.Lock()
+	samples := append([]delaySample(nil), v.delayTimeline[button]...)
+	start := v.sessionStart
+	v.mu.Unlock()
+
+	if len(samples) == 0 {
+		v.delayChart.SetData(nil, 0, "Start protection to record", "")
+		return
+	}
+
+	now := time.Now()
+	points := make([]components.StepPoint, len(samples))
+	for i, s := range samples {
+		points[i] = components.StepPoint{X: s.time.Sub(start).Seconds(), Y: float64(s.delay.Milliseconds())}
+	}
+	v.delayChart.SetData(points, now.Sub(start).Seconds(), start.Format("15:04"), now.Format("15:04"))
+}
+
+// histogramLabels names the buckets of a timing histogram
+func histogramLabels() []string {
+	labels := make([]string, 0, len(filter.HistogramBuckets)+1)
+	for _, bound := range filter.HistogramBuckets {
+		if bound >= time.Second {
+			labels = append(labels, fmt.Sprintf("%gs", bound.Seconds()))
+		} else {
+			labels = append(labels, fmt.Sprintf("%d", bound.Milliseconds()))
+		}
+	}
+	return append(labels, "more")
+}
+
+func histogramValues(h filter.Histogram) []float64 {
+	values := make([]float64, len(h.Counts))
+	for i, n := range h.Counts {
+		values[i] = float64(n)
+	}
+	return values
+}
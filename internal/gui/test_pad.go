@@ -0,0 +1,229 @@
+package gui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+
+	"click-guardian/internal/filter"
+	"click-guardian/internal/gui/components"
+	"click-guardian/internal/version"
+)
+
+// maxTestPadEntries is how many events the test pad keeps
+const maxTestPadEntries = 500
+
+// testPadEntry is a raw press or release made on the test pad
+type testPadEntry struct {
+	time      time.Time
+	kind      filter.Kind
+	button    filter.Button
+	since     time.Duration // Hold time of a release, gap since the previous release of a press
+	decision  filter.Decision
+	simulated bool
+}
+
+// String formats the entry as one line of the event list; the report adds
+// the time and the delay that was applied
+func (e testPadEntry) String() string {
+	timing := ""
+	if e.since > 0 {
+		label := "gap"
+		if e.kind == filter.KindUp {
+			label = "hold"
+		}
+		timing = fmt.Sprintf("%s %d ms", label, e.since.Milliseconds())
+	}
+
+	result := "allowed"
+	if e.decision.DoubleClick {
+		result = "allowed double-click"
+	}
+	if !e.decision.Allow {
+		result = "BLOCKED " + e.decision.Reason.String()
+	}
+	if e.simulated {
+		result += " *"
+	}
+	return fmt.Sprintf("%-6s %-4s %-12s %s", e.button, e.kind, timing, result)
+}
+
+// testPad shows every press and release made on a target area together
+// with the filter decision. While protection runs the decisions come from
+// the hook; otherwise they are simulated with the current settings.
+type testPad struct {
+	pad    *components.ClickPad
+	list   *widget.List
+	status *widget.Label
+
+	// Only accessed on the main thread
+	entries  []testPadEntry
+	lastDown map[filter.Button]time.Time
+	lastUp   map[filter.Button]time.Time
+	pending  map[filter.Button]bool // Presses made on the pad whose release is still due
+	engine   *filter.Engine         // Simulates decisions while protection is off
+}
+
+// setupTestPadTab builds the Test tab
+func (app *Application) setupTestPadTab() fyne.CanvasObject {
+	t := &testPad{
+		pad:      components.NewClickPad("Click here"),
+		status:   widget.NewLabel(""),
+		lastDown: make(map[filter.Button]time.Time),
+		lastUp:   make(map[filter.Button]time.Time),
+		pending:  make(map[filter.Button]bool),
+	}
+	t.status.Wrapping = fyne.TextWrapWord
+	app.testPad = t
+
+	t.list = widget.NewList(
+		func() int { return len(t.entries) },
+		func() fyne.CanvasObject {
+			label := widget.NewLabel("")
+			label.TextStyle = fyne.TextStyle{Monospace: true}
+			label.Truncation = fyne.TextTruncateEllipsis
+			return label
+		},
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			// Newest first
+			item.(*widget.Label).SetText(t.entries[len(t.entries)-1-id].String())
+		},
+	)
+
+	t.pad.OnMouseDown = func(button desktop.MouseButton) { app.simulateTestPad(filter.KindDown, button) }
+	t.pad.OnMouseUp = func(button desktop.MouseButton) { app.simulateTestPad(filter.KindUp, button) }
+
+	clearButton := widget.NewButton("Clear", func() {
+		t.entries = nil
+		app.resetTestPad()
+		t.list.Refresh()
+	})
+	copyButton := widget.NewButton("Copy Report", func() {
+		app.app.Clipboard().SetContent(app.testPadReport())
+		app.logger.Log("📋 Test pad report copied to the clipboard")
+	})
+
+	controls := container.NewHBox(layout.NewSpacer(), copyButton, clearButton)
+	top := container.NewVBox(t.pad, t.status, controls)
+	return container.NewBorder(top, nil, nil, nil, t.list)
+}
+
+// startTestPad starts showing events while the Test tab is open
+func (app *Application) startTestPad() {
+	app.resetTestPad()
+	app.addObserver("testpad", func(ev filter.Event, d filter.Decision) {
+		if ev.Kind != filter.KindDown && ev.Kind != filter.KindUp {
+			return
+		}
+		// Read now, the pointer may have moved by the time the UI runs
+		hovered := app.testPad.pad.Hovered()
+		fyne.Do(func() {
+			app.recordTestPad(ev, d, false, hovered)
+		})
+	})
+}
+
+// stopTestPad stops showing events when the Test tab is left
+func (app *Application) stopTestPad() {
+	app.removeObserver("testpad")
+}
+
+// resetTestPad starts a new simulation with the current settings
+func (app *Application) resetTestPad() {
+	t := app.testPad
+	t.engine = filter.NewEngine(app.config.FilterConfig())
+	t.lastDown = make(map[filter.Button]time.Time)
+	t.lastUp = make(map[filter.Button]time.Time)
+	t.pending = make(map[filter.Button]bool)
+	t.showSource(!app.isRunning)
+}
+
+// showSource tells where the decisions shown come from
+func (t *testPad) showSource(simulated bool) {
+	if simulated {
+		t.status.SetText("Protection is off: decisions marked * are simulated with the current settings.")
+	} else {
+		t.status.SetText("Protection is active: decisions are the ones the hook made.")
+	}
+}
+
+// simulateTestPad runs a click on the pad through a local engine while the
+// hook is not running
+func (app *Application) simulateTestPad(kind filter.Kind, mouseButton desktop.MouseButton) {
+	if app.isRunning {
+		return
+	}
+
+	var button filter.Button
+	switch mouseButton {
+	case desktop.MouseButtonPrimary:
+		button = filter.ButtonLeft
+	case desktop.MouseButtonSecondary:
+		button = filter.ButtonRight
+	case desktop.MouseButtonTertiary:
+		button = filter.ButtonMiddle
+	default:
+		return
+	}
+
+	ev := filter.Event{Kind: kind, Button: button, Time: time.Now()}
+	app.recordTestPad(ev, app.testPad.engine.Process(ev), true, true)
+}
+
+// recordTestPad adds a press made on the pad, or the release of one, to the list
+func (app *Application) recordTestPad(ev filter.Event, d filter.Decision, simulated, hovered bool) {
+	t := app.testPad
+	entry := testPadEntry{time: ev.Time, kind: ev.Kind, button: ev.Button, decision: d, simulated: simulated}
+
+	switch ev.Kind {
+	case filter.KindDown:
+		if !hovered {
+			return
+		}
+		if last, ok := t.lastUp[ev.Button]; ok {
+			entry.since = ev.Time.Sub(last)
+		}
+		t.lastDown[ev.Button] = ev.Time
+		t.pending[ev.Button] = true
+	case filter.KindUp:
+		// Releases count when the press was made on the pad, even after a drag
+		if !t.pending[ev.Button] {
+			return
+		}
+		entry.since = ev.Time.Sub(t.lastDown[ev.Button])
+		t.lastUp[ev.Button] = ev.Time
+		t.pending[ev.Button] = false
+	}
+
+	t.showSource(simulated)
+	t.entries = append(t.entries, entry)
+	if len(t.entries) > maxTestPadEntries {
+		t.entries = t.entries[len(t.entries)-maxTestPadEntries:]
+	}
+	t.list.Refresh()
+}
+
+// testPadReport describes the settings and the recorded events for a bug report
+func (app *Application) testPadReport() string {
+	info := version.GetAppInfo()
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Click Guardian v%s on %s/%s\n", info.Version, info.Platform, info.Arch)
+	fmt.Fprintf(&sb, "Protection active: %v, delay: %d ms\n", app.isRunning, app.config.DelayMs)
+	fmt.Fprintf(&sb, "Strategy: %s, double-click window: %d ms\n", app.config.FilterStrategy, app.config.DoubleClickWindowMs)
+	for _, button := range filter.Buttons() {
+		fmt.Fprintf(&sb, "%s: enabled %v, delay %d ms\n", button,
+			app.config.ButtonEnabled(button), app.config.FilterConfig().DelayFor(button).Milliseconds())
+	}
+	sb.WriteString("\n")
+	for _, entry := range app.testPad.entries {
+		fmt.Fprintf(&sb, "%s  %s  (delay %d ms)\n", entry.time.Format("15:04:05.000"), entry, entry.decision.Delay.Milliseconds())
+	}
+	return sb.String()
+}
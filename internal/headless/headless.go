@@ -0,0 +1,384 @@
+package headless
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"click-guardian/internal/config"
+	"click-guardian/internal/control"
+	"click-guardian/internal/filter"
+	"click-guardian/internal/health"
+	"click-guardian/internal/history"
+	"click-guardian/internal/hooks"
+	"click-guardian/internal/logger"
+	"click-guardian/internal/metrics"
+	"click-guardian/internal/profiles"
+	"click-guardian/internal/version"
+)
+
+// Options control a headless run
+type Options struct {
+	LogFile string // Also append log lines to this file when set
+}
+
+// runner owns the hook of a headless run and implements control.Controller
+type runner struct {
+	log      *log.Logger
+	config   *config.Config
+	hook     hooks.MouseHook
+	keyboard hooks.KeyboardHook
+	hotkeys  hooks.HotkeyHook
+	events   chan hooks.Event
+	control  *control.Server
+	history  *history.Store    // nil when the lifetime statistics are unavailable
+	watcher  *profiles.Watcher // nil without application profiles
+
+	mu           sync.Mutex
+	isRunning    bool
+	snoozeTimer  *time.Timer // Starts protection again, nil unless snoozed
+	snoozedUntil time.Time
+}
+
+// Run filters mouse input with the saved configuration until SIGINT or
+// SIGTERM is received. Log lines go to stdout and the optional log file.
+func Run(opts Options) error {
+	var out io.Writer = os.Stdout
+	if opts.LogFile != "" {
+		f, err := os.OpenFile(opts.LogFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return fmt.Errorf("failed to open log file: %v", err)
+		}
+		defer f.Close()
+		out = io.MultiWriter(os.Stdout, f)
+	}
+
+	r := &runner{
+		log:      log.New(out, "", log.LstdFlags),
+		config:   config.LoadConfig(),
+		hook:     hooks.NewMouseHook(),
+		keyboard: hooks.NewKeyboardHook(),
+		hotkeys:  hooks.NewHotkeyHook(),
+		events:   make(chan hooks.Event, 100),
+	}
+	if !r.hook.IsSupported() {
+		return fmt.Errorf("mouse hooking not supported on this platform")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Owning the control socket keeps a second instance from filtering twice
+	server, err := control.Listen()
+	if errors.Is(err, control.ErrAlreadyRunning) {
+		return err
+	}
+
+	// Opened before serving so control requests can read the history
+	if path, err := history.DefaultPath(); err != nil {
+		r.log.Printf("⚠️ Lifetime statistics unavailable: %v", err)
+	} else if store, err := history.Open(path, r.config.StatsRetentionDays); err != nil {
+		r.log.Printf("⚠️ Lifetime statistics unavailable: %v", err)
+	} else {
+		r.history = store
+		collector := history.NewCollector(store, r.hook)
+		done := make(chan struct{})
+		go collector.Run(time.Minute, done, func(err error) {
+			r.log.Printf("⚠️ Failed to save statistics: %v", err)
+		})
+		go r.monitorHealth(time.Minute, done)
+		defer collector.Flush()
+		defer close(done)
+	}
+
+	if err == nil {
+		err = server.Serve(r)
+	}
+	if err != nil {
+		r.log.Printf("⚠️ Control socket unavailable: %v", err)
+	} else {
+		r.control = server
+		defer server.Close()
+	}
+
+	if r.config.MetricsEnabled {
+		if server, err := metrics.Listen(r.config.MetricsAddress, r.hook); err != nil {
+			r.log.Printf("⚠️ Metrics exporter unavailable: %v", err)
+		} else {
+			r.log.Printf("📈 Metrics available at http://%s/metrics", server.Address())
+			defer server.Close()
+		}
+	}
+
+	r.startHotkeys()
+	defer r.hotkeys.Stop()
+
+	if err := r.StartProtection(); err != nil {
+		return err
+	}
+
+	var failure error
+loop:
+	for {
+		select {
+		case <-ctx.Done():
+			r.log.Printf("Received shutdown signal")
+			break loop
+		case ev := <-r.events:
+			r.logEvent(ev)
+			if ev.Type == hooks.EventHotkey {
+				r.toggleProtection()
+				continue
+			}
+			// Losing a single device is survivable, losing the hook is not
+			if ev.Type == hooks.EventError && ev.Fatal {
+				failure = fmt.Errorf("protection stopped: %v", ev.Err)
+				break loop
+			}
+		}
+	}
+
+	r.StopProtection()
+	return failure
+}
+
+// logEvent prints a hook event and forwards it to control clients
+func (r *runner) logEvent(ev hooks.Event) {
+	msg := logger.FormatEvent(ev)
+	if msg != "" {
+		r.log.Print(msg)
+	}
+	if r.control != nil {
+		r.control.Publish(ev, msg)
+	}
+}
+
+// startHotkeys watches for the pause hotkey and the bypass modifier.
+// Protection works without them.
+func (r *runner) startHotkeys() {
+	cfg := r.config.HotkeyConfig()
+	if !cfg.Enabled() {
+		return
+	}
+	if err := r.hotkeys.Start(cfg, r.events); err != nil {
+		r.log.Printf("⚠️ Global hotkeys unavailable: %v", err)
+		return
+	}
+	if cfg.Toggle.Key != "" {
+		r.log.Printf("Press %s to pause or resume protection", cfg.Toggle)
+	}
+	if cfg.Bypass != 0 {
+		r.log.Printf("Hold %s to let clicks through unfiltered", cfg.Bypass)
+	}
+}
+
+// toggleProtection pauses or resumes protection when the hotkey is pressed
+func (r *runner) toggleProtection() {
+	r.mu.Lock()
+	running := r.isRunning
+	r.mu.Unlock()
+
+	if running {
+		r.StopProtection()
+	} else if err := r.StartProtection(); err != nil {
+		r.log.Printf("⚠️ %v", err)
+	}
+}
+
+// monitorHealth logs a warning whenever a button's health score drops
+// below the configured threshold
+func (r *runner) monitorHealth(interval time.Duration, stop <-chan struct{}) {
+	monitor := health.NewMonitor(r.config.HealthWarningScore)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		for _, report := range monitor.Check(health.Evaluate(r.history.Days(health.WindowDays), time.Now())) {
+			r.log.Printf("⚠️ %s button health is %d/100: %s", report.Button, report.Score, report.Recommendation())
+		}
+		select {
+		case <-ticker.C:
+		case <-stop:
+			return
+		}
+	}
+}
+
+// Status implements control.Controller
+func (r *runner) Status() control.Status {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return control.Status{
+		Running:       r.isRunning,
+		DelayMs:       r.config.DelayMs,
+		BlockedClicks: r.hook.GetBlockedCount(),
+		Version:       version.GetVersionString(),
+		Mode:          "headless",
+		SnoozedUntil:  r.snoozedUntil,
+	}
+}
+
+// StartProtection implements control.Controller
+func (r *runner) StartProtection() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cancelSnooze()
+	if r.isRunning {
+		return nil
+	}
+
+	r.log.Printf("Starting double-click protection with %d ms delay", r.config.DelayMs)
+	for _, button := range filter.Buttons() {
+		if !r.config.ButtonEnabled(button) {
+			r.log.Printf("%s button is not filtered", button)
+		} else if own := r.config.Buttons[config.ButtonKey(button)].DelayMs; own > 0 {
+			r.log.Printf("%s button uses its own %d ms delay", button, own)
+		}
+	}
+
+	if err := r.hook.Start(r.config.FilterConfig(), r.events); err != nil {
+		return fmt.Errorf("failed to start protection: %v", err)
+	}
+	r.isRunning = true
+
+	if len(r.config.Profiles) > 0 {
+		r.log.Printf("Watching the foreground application for %d profiles", len(r.config.Profiles))
+	}
+	r.watcher = profiles.Start(r.config, r.hook, func(s profiles.Switch) {
+		r.log.Print(s.Describe())
+	})
+
+	// Mouse protection keeps running when the keyboards cannot be grabbed
+	if r.config.KeyboardEnabled {
+		if err := r.keyboard.Start(r.config.KeyFilterConfig(), r.events); err != nil {
+			r.log.Printf("⚠️ Keyboard chatter filter unavailable: %v", err)
+		} else {
+			r.log.Printf("Filtering keyboard chatter with a %d ms threshold", r.config.KeyThresholdMs)
+		}
+	}
+	return nil
+}
+
+// StopProtection implements control.Controller
+func (r *runner) StopProtection() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cancelSnooze()
+	if !r.isRunning {
+		return nil
+	}
+
+	r.watcher.Stop()
+	r.watcher = nil
+	r.keyboard.Stop()
+	r.hook.Stop()
+	r.isRunning = false
+	for drained := false; !drained; {
+		select {
+		case ev := <-r.events:
+			r.logEvent(ev)
+		default:
+			drained = true
+		}
+	}
+
+	stats := r.hook.Stats()
+	r.log.Printf("Stopped double-click protection: %d clicks allowed, %d blocked, %d wheel ticks blocked",
+		stats.TotalAllowed(), stats.TotalBlockedClicks(), stats.WheelBlocked)
+	if r.config.KeyboardEnabled {
+		keys := r.keyboard.Stats()
+		r.log.Printf("Key presses: %d allowed, %d blocked as chatter", keys.Allowed, keys.TotalBlocked())
+	}
+	return nil
+}
+
+// Snooze implements control.Controller. Protection is stopped and started
+// again once d has passed.
+func (r *runner) Snooze(d time.Duration) error {
+	r.mu.Lock()
+	active := r.isRunning || r.snoozeTimer != nil
+	r.mu.Unlock()
+	if !active {
+		return fmt.Errorf("protection is not active")
+	}
+
+	r.StopProtection()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.snoozedUntil = time.Now().Add(d)
+	var timer *time.Timer
+	timer = time.AfterFunc(d, func() {
+		// A snooze replaced or cancelled meanwhile does not resume anything
+		r.mu.Lock()
+		current := r.snoozeTimer == timer
+		r.mu.Unlock()
+		if !current {
+			return
+		}
+		r.log.Printf("⏰ Snooze is over, resuming protection")
+		if err := r.StartProtection(); err != nil {
+			r.log.Printf("⚠️ %v", err)
+		}
+	})
+	r.snoozeTimer = timer
+	r.log.Printf("😴 Protection snoozed for %.0f minutes, resuming at %s", d.Minutes(), r.snoozedUntil.Format("15:04:05"))
+	return nil
+}
+
+// cancelSnooze forgets a pending snooze. r.mu must be held.
+func (r *runner) cancelSnooze() {
+	if r.snoozeTimer != nil {
+		r.snoozeTimer.Stop()
+		r.snoozeTimer = nil
+	}
+	r.snoozedUntil = time.Time{}
+}
+
+// SetDelay implements control.Controller. The new delay is saved and takes
+// effect immediately; active protection keeps its state and profile.
+func (r *runner) SetDelay(ms int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.config.DelayMs = ms
+	if err := r.config.Save(); err != nil {
+		r.log.Printf("⚠️ Failed to save delay setting: %v", err)
+	}
+	r.log.Printf("Delay set to %d ms via control socket", ms)
+
+	if !r.isRunning {
+		return nil
+	}
+	if r.watcher != nil {
+		r.watcher.Update(r.config)
+	} else {
+		r.hook.SetConfig(r.config.FilterConfig())
+	}
+	return nil
+}
+
+// ShowWindow implements control.Controller
+func (r *runner) ShowWindow() error {
+	return fmt.Errorf("running in headless mode, there is no window to show")
+}
+
+// Stats implements control.Controller
+func (r *runner) Stats() filter.Stats {
+	return r.hook.Stats()
+}
+
+// Health implements control.Controller
+func (r *runner) Health() ([]health.Report, error) {
+	if r.history == nil {
+		return nil, fmt.Errorf("lifetime statistics are unavailable")
+	}
+	return health.Evaluate(r.history.Days(health.WindowDays), time.Now()), nil
+}
@@ -0,0 +1,195 @@
+package health
+
+import (
+	"strings"
+	"time"
+
+	"click-guardian/internal/filter"
+	"click-guardian/internal/history"
+)
+
+const (
+	WindowDays = 30  // Days of history a score is based on
+	RecentDays = 7   // Days compared against the rest of the window for the trend
+	MinClicks  = 100 // Presses needed before a button is scored
+
+	slowBounceMin = 20 * time.Millisecond  // Bounces slower than this start slipping past short delays
+	slowBounceMax = 100 * time.Millisecond // Switch chatter is never slower than this
+
+	maxRatePenalty  = 60 // Points lost to the bounce rate
+	maxSlowPenalty  = 20 // Points lost to slow bounces
+	maxTrendPenalty = 20 // Points lost to a rising bounce rate
+)
+
+// Level classifies a score
+type Level int
+
+const (
+	LevelUnknown Level = iota // Not enough clicks recorded
+	LevelGood
+	LevelWorn
+	LevelFailing
+)
+
+func (l Level) String() string {
+	switch l {
+	case LevelGood:
+		return "good"
+	case LevelWorn:
+		return "worn"
+	case LevelFailing:
+		return "failing"
+	default:
+		return "unknown"
+	}
+}
+
+// LevelFor classifies a score
+func LevelFor(score int) Level {
+	switch {
+	case score < 0:
+		return LevelUnknown
+	case score < 50:
+		return LevelFailing
+	case score < 80:
+		return LevelWorn
+	default:
+		return LevelGood
+	}
+}
+
+// Report is the health of one button
+type Report struct {
+	Button filter.Button
+	Score  int // 0-100, -1 when fewer than MinClicks presses were recorded
+	Level  Level
+	Clicks int // Presses in the window, blocked ones included
+
+	BounceRate     float64 // Share of presses blocked
+	SlowBounceRate float64 // Share of presses following the previous one by 20-100 ms
+	Trend          float64 // Bounce rate of the recent days minus the earlier days
+}
+
+// Recommendation tells what to do about the button
+func (r Report) Recommendation() string {
+	switch r.Level {
+	case LevelGood:
+		return "No action needed"
+	case LevelWorn:
+		return "The switch shows wear, keep an eye on it"
+	case LevelFailing:
+		return "The switch is failing, consider replacing the mouse"
+	default:
+		return "Not enough clicks recorded yet"
+	}
+}
+
+// counts sums the statistics of a button over some days
+type counts struct {
+	allowed   int
+	blocked   int
+	slow      int
+	intervals int
+}
+
+func (c *counts) add(b *history.ButtonDay) {
+	c.allowed += b.Allowed
+	c.blocked += b.BlockedClicks()
+	c.intervals += b.Intervals.Count
+	for i, bound := range filter.HistogramBuckets {
+		if bound > slowBounceMin && bound <= slowBounceMax {
+			c.slow += b.Intervals.Counts[i]
+		}
+	}
+}
+
+func (c counts) clicks() int {
+	return c.allowed + c.blocked
+}
+
+func (c counts) bounceRate() float64 {
+	if c.clicks() == 0 {
+		return 0
+	}
+	return float64(c.blocked) / float64(c.clicks())
+}
+
+// Evaluate scores every button from the last WindowDays of days. Each
+// button starts at 100 and loses points for the share of blocked presses,
+// for bounces slow enough to get past short delays and for a bounce rate
+// that rose over the last RecentDays.
+func Evaluate(days []history.Day, now time.Time) []Report {
+	windowStart := now.AddDate(0, 0, -(WindowDays - 1)).Format("2006-01-02")
+	recentStart := now.AddDate(0, 0, -(RecentDays - 1)).Format("2006-01-02")
+
+	reports := make([]Report, 0, len(filter.Buttons()))
+	for _, button := range filter.Buttons() {
+		key := strings.ToLower(button.String())
+		var total, recent, earlier counts
+		for _, day := range days {
+			b, ok := day.Buttons[key]
+			if !ok || day.Date < windowStart {
+				continue
+			}
+			total.add(b)
+			if day.Date >= recentStart {
+				recent.add(b)
+			} else {
+				earlier.add(b)
+			}
+		}
+		reports = append(reports, score(button, total, recent, earlier))
+	}
+	return reports
+}
+
+func score(button filter.Button, total, recent, earlier counts) Report {
+	r := Report{Button: button, Score: -1, Clicks: total.clicks()}
+	if r.Clicks < MinClicks {
+		return r
+	}
+
+	r.BounceRate = total.bounceRate()
+	if total.intervals > 0 {
+		r.SlowBounceRate = float64(total.slow) / float64(total.intervals)
+	}
+	if recent.clicks() >= MinClicks && earlier.clicks() >= MinClicks {
+		r.Trend = recent.bounceRate() - earlier.bounceRate()
+	}
+
+	// A 10% bounce rate, 5% slow bounces or a rise of 5 points cost the maximum
+	penalty := min(r.BounceRate*600, maxRatePenalty) +
+		min(r.SlowBounceRate*400, maxSlowPenalty) +
+		min(max(r.Trend, 0)*400, maxTrendPenalty)
+	r.Score = max(100-int(penalty+0.5), 0)
+	r.Level = LevelFor(r.Score)
+	return r
+}
+
+// Monitor finds buttons whose score drops below a threshold
+type Monitor struct {
+	threshold int
+	warned    map[filter.Button]bool
+}
+
+// NewMonitor creates a monitor, a threshold of 0 never warns
+func NewMonitor(threshold int) *Monitor {
+	return &Monitor{threshold: threshold, warned: make(map[filter.Button]bool)}
+}
+
+// Check returns the reports that fell below the threshold since the
+// previous check. A button is reported again only after it recovered.
+func (m *Monitor) Check(reports []Report) []Report {
+	var dropped []Report
+	for _, r := range reports {
+		if r.Score < 0 {
+			continue
+		}
+		below := r.Score < m.threshold
+		if below && !m.warned[r.Button] {
+			dropped = append(dropped, r)
+		}
+		m.warned[r.Button] = below
+	}
+	return dropped
+}
@@ -0,0 +1,238 @@
+package health
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"click-guardian/internal/filter"
+	"click-guardian/internal/history"
+)
+
+// now is the time reports are evaluated at
+var now = time.Date(2024, 3, 30, 12, 0, 0, 0, time.Local)
+
+// leftDay returns the record of a day ago days before now in which the left
+// button was pressed allowed+blocked times. The allowed presses followed the
+// previous one by the given intervals, cycled.
+func leftDay(ago, allowed, blocked int, intervals ...time.Duration) history.Day {
+	b := &history.ButtonDay{
+		Allowed: allowed,
+		Blocked: map[string]int{filter.ReasonRapidDown.String(): blocked},
+	}
+	for i := 0; i < allowed && len(intervals) > 0; i++ {
+		b.Intervals.Observe(intervals[i%len(intervals)])
+	}
+	return history.Day{
+		Date:    now.AddDate(0, 0, -ago).Format("2006-01-02"),
+		Buttons: map[string]*history.ButtonDay{"left": b},
+	}
+}
+
+// month returns WindowDays days of records, made by fn from the age of the day
+func month(fn func(ago int) history.Day) []history.Day {
+	var days []history.Day
+	for ago := WindowDays - 1; ago >= 0; ago-- {
+		days = append(days, fn(ago))
+	}
+	return days
+}
+
+func leftReport(t *testing.T, days []history.Day) Report {
+	t.Helper()
+	for _, r := range Evaluate(days, now) {
+		if r.Button == filter.ButtonLeft {
+			return r
+		}
+	}
+	t.Fatal("no report for the left button")
+	return Report{}
+}
+
+func TestEvaluateScore(t *testing.T) {
+	slow := 40 * time.Millisecond
+	fast := 400 * time.Millisecond
+
+	tests := []struct {
+		name  string
+		days  []history.Day
+		score int
+		level Level
+	}{
+		{
+			name:  "too few clicks",
+			days:  []history.Day{leftDay(0, 90, 9)},
+			score: -1,
+			level: LevelUnknown,
+		},
+		{
+			name: "healthy",
+			days: month(func(ago int) history.Day {
+				return leftDay(ago, 100, 0, fast)
+			}),
+			score: 100,
+			level: LevelGood,
+		},
+		{
+			// 5% blocked costs 30 points
+			name: "worn",
+			days: month(func(ago int) history.Day {
+				return leftDay(ago, 95, 5)
+			}),
+			score: 70,
+			level: LevelWorn,
+		},
+		{
+			// 10% blocked costs the maximum 60 points
+			name: "failing",
+			days: month(func(ago int) history.Day {
+				return leftDay(ago, 90, 10)
+			}),
+			score: 40,
+			level: LevelFailing,
+		},
+		{
+			// One allowed press in 40 follows the previous one by 40ms
+			name: "slow bounces",
+			days: month(func(ago int) history.Day {
+				intervals := make([]time.Duration, 40)
+				for i := range intervals {
+					intervals[i] = fast
+				}
+				intervals[0] = slow
+				return leftDay(ago, 120, 0, intervals...)
+			}),
+			score: 90,
+			level: LevelGood,
+		},
+		{
+			name: "slow bounces cap",
+			days: month(func(ago int) history.Day {
+				return leftDay(ago, 100, 0, slow, fast)
+			}),
+			score: 80,
+			level: LevelGood,
+		},
+		{
+			// Only the last WindowDays days count
+			name: "old bounces forgotten",
+			days: append([]history.Day{leftDay(WindowDays, 0, 1000)}, month(func(ago int) history.Day {
+				return leftDay(ago, 100, 0)
+			})...),
+			score: 100,
+			level: LevelGood,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := leftReport(t, tt.days)
+			if r.Score != tt.score || r.Level != tt.level {
+				t.Errorf("score = %d (%s), want %d (%s); bounce rate %.3f, slow %.3f, trend %.3f",
+					r.Score, r.Level, tt.score, tt.level, r.BounceRate, r.SlowBounceRate, r.Trend)
+			}
+		})
+	}
+}
+
+func TestEvaluateTrend(t *testing.T) {
+	tests := []struct {
+		name                    string
+		earlierRate, recentRate int // Blocked presses per 100
+		trend                   float64
+		score                   int
+	}{
+		{
+			// 1.7% overall costs 10 points, the 3 point rise 12 more
+			name:        "rising",
+			earlierRate: 1,
+			recentRate:  4,
+			trend:       0.03,
+			score:       78,
+		},
+		{
+			name:        "falling",
+			earlierRate: 4,
+			recentRate:  1,
+			trend:       -0.03,
+			score:       80,
+		},
+		{
+			name:        "steady",
+			earlierRate: 2,
+			recentRate:  2,
+			trend:       0,
+			score:       88,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := leftReport(t, month(func(ago int) history.Day {
+				rate := tt.earlierRate
+				if ago < RecentDays {
+					rate = tt.recentRate
+				}
+				return leftDay(ago, 100-rate, rate)
+			}))
+			if math.Abs(r.Trend-tt.trend) > 1e-9 {
+				t.Errorf("trend = %.4f, want %.4f", r.Trend, tt.trend)
+			}
+			if r.Score != tt.score {
+				t.Errorf("score = %d, want %d", r.Score, tt.score)
+			}
+		})
+	}
+}
+
+func TestEvaluateTrendNeedsBothPeriods(t *testing.T) {
+	// A new mouse has no earlier days to compare with
+	var days []history.Day
+	for ago := RecentDays - 1; ago >= 0; ago-- {
+		days = append(days, leftDay(ago, 90, 10))
+	}
+	if r := leftReport(t, days); r.Trend != 0 {
+		t.Errorf("trend = %.3f without earlier days, want 0", r.Trend)
+	}
+}
+
+func TestEvaluateIgnoresSpuriousUps(t *testing.T) {
+	day := leftDay(0, 100, 0)
+	day.Buttons["left"].Blocked[filter.ReasonSpuriousUp.String()] = 50
+
+	r := leftReport(t, []history.Day{day})
+	if r.Clicks != 100 || r.BounceRate != 0 || r.Score != 100 {
+		t.Errorf("got %d clicks, bounce rate %.3f, score %d; want 100 clicks scoring 100",
+			r.Clicks, r.BounceRate, r.Score)
+	}
+}
+
+func TestMonitor(t *testing.T) {
+	m := NewMonitor(60)
+	report := func(score int) []Report {
+		return []Report{
+			{Button: filter.ButtonLeft, Score: score},
+			{Button: filter.ButtonRight, Score: -1},
+		}
+	}
+
+	for i, tt := range []struct {
+		score int
+		warn  bool
+	}{
+		{90, false},
+		{55, true},
+		{50, false}, // Still below, already warned
+		{70, false}, // Recovered
+		{40, true},
+	} {
+		dropped := m.Check(report(tt.score))
+		if warned := len(dropped) == 1 && dropped[0].Button == filter.ButtonLeft; warned != tt.warn || len(dropped) > 1 {
+			t.Errorf("check %d at score %d: dropped %v, want warning %v", i, tt.score, dropped, tt.warn)
+		}
+	}
+
+	if dropped := NewMonitor(0).Check(report(0)); len(dropped) != 0 {
+		t.Errorf("threshold 0 warned about %v", dropped)
+	}
+}
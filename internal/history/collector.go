@@ -0,0 +1,152 @@
+package history
+
+import (
+	"sync"
+	"time"
+
+	"click-guardian/internal/filter"
+)
+
+// Source provides the statistics of the running hook
+type Source interface {
+	Stats() filter.Stats
+}
+
+// Collector moves the growth of the hook statistics into a Store
+type Collector struct {
+	store  *Store
+	source Source
+
+	mu   sync.Mutex
+	last filter.Stats
+}
+
+// NewCollector starts collecting from the current state of source
+func NewCollector(store *Store, source Source) *Collector {
+	return &Collector{store: store, source: source, last: source.Stats()}
+}
+
+// Flush adds what was counted since the previous flush to the store and
+// saves it. Nothing is written when no event happened.
+func (c *Collector) Flush() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	current := c.source.Stats()
+	delta := diff(c.last, current)
+	c.last = current
+
+	if isEmpty(delta) {
+		return nil
+	}
+	c.store.Add(delta, time.Now())
+	return c.store.Save()
+}
+
+// Run flushes every interval until stop is closed, then flushes once more.
+// Errors are passed to report.
+func (c *Collector) Run(interval time.Duration, stop <-chan struct{}, report func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := c.Flush(); err != nil && report != nil {
+				report(err)
+			}
+		case <-stop:
+			if err := c.Flush(); err != nil && report != nil {
+				report(err)
+			}
+			return
+		}
+	}
+}
+
+// diff returns what was counted between two snapshots. The statistics are
+// reset as a whole, so when any counter went down everything in cur is new.
+func diff(prev, cur filter.Stats) filter.Stats {
+	if wasReset(prev, cur) {
+		prev = filter.NewStats()
+	}
+
+	delta := filter.NewStats()
+
+	for button, n := range cur.Allowed {
+		if d := n - prev.Allowed[button]; d > 0 {
+			delta.Allowed[button] = d
+		}
+	}
+	for button, reasons := range cur.Blocked {
+		for reason, n := range reasons {
+			if d := n - prev.Blocked[button][reason]; d > 0 {
+				if delta.Blocked[button] == nil {
+					delta.Blocked[button] = make(map[filter.Reason]int)
+				}
+				delta.Blocked[button][reason] = d
+			}
+		}
+	}
+	for button, h := range cur.Intervals {
+		if d := diffHistogram(prev.Intervals[button], h); d.Count > 0 {
+			delta.Intervals[button] = d
+		}
+	}
+	for button, h := range cur.Holds {
+		if d := diffHistogram(prev.Holds[button], h); d.Count > 0 {
+			delta.Holds[button] = d
+		}
+	}
+	delta.WheelAllowed = cur.WheelAllowed - prev.WheelAllowed
+	delta.WheelBlocked = cur.WheelBlocked - prev.WheelBlocked
+	return delta
+}
+
+// wasReset reports whether any counter of cur is below its value in prev
+func wasReset(prev, cur filter.Stats) bool {
+	for button, n := range prev.Allowed {
+		if cur.Allowed[button] < n {
+			return true
+		}
+	}
+	for button, reasons := range prev.Blocked {
+		for reason, n := range reasons {
+			if cur.Blocked[button][reason] < n {
+				return true
+			}
+		}
+	}
+	histogramReset := func(p, c filter.Histogram) bool {
+		for i, n := range p.Counts {
+			if c.Counts[i] < n {
+				return true
+			}
+		}
+		return c.Count < p.Count
+	}
+	for button, h := range prev.Intervals {
+		if histogramReset(h, cur.Intervals[button]) {
+			return true
+		}
+	}
+	for button, h := range prev.Holds {
+		if histogramReset(h, cur.Holds[button]) {
+			return true
+		}
+	}
+	return cur.WheelAllowed < prev.WheelAllowed || cur.WheelBlocked < prev.WheelBlocked
+}
+
+func diffHistogram(prev, cur filter.Histogram) filter.Histogram {
+	d := filter.Histogram{Sum: cur.Sum - prev.Sum, Count: cur.Count - prev.Count}
+	for i := range cur.Counts {
+		d.Counts[i] = cur.Counts[i] - prev.Counts[i]
+	}
+	return d
+}
+
+func isEmpty(s filter.Stats) bool {
+	return len(s.Allowed) == 0 && len(s.Blocked) == 0 && len(s.Intervals) == 0 &&
+		len(s.Holds) == 0 && s.WheelAllowed == 0 && s.WheelBlocked == 0
+}
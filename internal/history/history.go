@@ -0,0 +1,207 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"click-guardian/internal/config"
+	"click-guardian/internal/filter"
+)
+
+const dateLayout = "2006-01-02"
+
+// ButtonDay holds the statistics of one button on one day
+type ButtonDay struct {
+	Allowed   int              `json:"allowed"`
+	Blocked   map[string]int   `json:"blocked"` // Per block reason
+	Intervals filter.Histogram `json:"intervals"`
+	Holds     filter.Histogram `json:"holds"`
+}
+
+// BlockedClicks returns the blocked presses, leaving out spurious releases
+func (b *ButtonDay) BlockedClicks() int {
+	total := 0
+	for reason, n := range b.Blocked {
+		if reason != filter.ReasonSpuriousUp.String() {
+			total += n
+		}
+	}
+	return total
+}
+
+// Day holds the statistics of one calendar day in local time
+type Day struct {
+	Date          string                `json:"date"`
+	Buttons       map[string]*ButtonDay `json:"buttons"`
+	BlockedByHour [24]int               `json:"blocked_by_hour"` // Blocked presses over all buttons
+	WheelAllowed  int                   `json:"wheel_allowed"`
+	WheelBlocked  int                   `json:"wheel_blocked"`
+}
+
+// Button returns the statistics of a button, creating them if needed
+func (d *Day) Button(button filter.Button) *ButtonDay {
+	key := strings.ToLower(button.String())
+	b, ok := d.Buttons[key]
+	if !ok {
+		b = &ButtonDay{Blocked: make(map[string]int)}
+		d.Buttons[key] = b
+	}
+	return b
+}
+
+// Store keeps per-day statistics in a JSON file
+type Store struct {
+	path      string
+	retention int // Days to keep, 0 keeps everything
+
+	mu   sync.Mutex
+	days map[string]*Day
+}
+
+// DefaultPath returns the statistics file in the config directory
+func DefaultPath() (string, error) {
+	dir, err := config.GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "stats.json"), nil
+}
+
+// Open loads the store at path, starting empty if the file does not exist
+func Open(path string, retentionDays int) (*Store, error) {
+	s := &Store{path: path, retention: retentionDays, days: make(map[string]*Day)}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read statistics: %v", err)
+	}
+
+	var days []*Day
+	if err := json.Unmarshal(data, &days); err != nil {
+		return nil, fmt.Errorf("failed to parse statistics: %v", err)
+	}
+	for _, d := range days {
+		if d.Buttons == nil {
+			d.Buttons = make(map[string]*ButtonDay)
+		}
+		for _, b := range d.Buttons {
+			if b.Blocked == nil {
+				b.Blocked = make(map[string]int)
+			}
+		}
+		s.days[d.Date] = d
+	}
+	return s, nil
+}
+
+// Add merges a statistics delta into the day and hour of at
+func (s *Store) Add(delta filter.Stats, at time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	date := at.Format(dateLayout)
+	day, ok := s.days[date]
+	if !ok {
+		day = &Day{Date: date, Buttons: make(map[string]*ButtonDay)}
+		s.days[date] = day
+	}
+
+	for _, button := range filter.Buttons() {
+		allowed := delta.Allowed[button]
+		blocked := delta.Blocked[button]
+		intervals := delta.Intervals[button]
+		holds := delta.Holds[button]
+		if allowed == 0 && len(blocked) == 0 && intervals.Count == 0 && holds.Count == 0 {
+			continue
+		}
+
+		b := day.Button(button)
+		b.Allowed += allowed
+		for reason, n := range blocked {
+			b.Blocked[reason.String()] += n
+		}
+		b.Intervals.Add(intervals)
+		b.Holds.Add(holds)
+	}
+	day.BlockedByHour[at.Hour()] += delta.TotalBlockedClicks()
+	day.WheelAllowed += delta.WheelAllowed
+	day.WheelBlocked += delta.WheelBlocked
+}
+
+// Days returns copies of the stored days from the last n days, oldest first.
+// n <= 0 returns every day.
+func (s *Store) Days(n int) []Day {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cutoff := ""
+	if n > 0 {
+		cutoff = time.Now().AddDate(0, 0, -(n - 1)).Format(dateLayout)
+	}
+
+	days := make([]Day, 0, len(s.days))
+	for date, d := range s.days {
+		if date >= cutoff {
+			days = append(days, copyDay(d))
+		}
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Date < days[j].Date })
+	return days
+}
+
+// Save prunes days past the retention and writes the store to disk
+func (s *Store) Save() error {
+	s.mu.Lock()
+	if s.retention > 0 {
+		cutoff := time.Now().AddDate(0, 0, -s.retention).Format(dateLayout)
+		for date := range s.days {
+			if date < cutoff {
+				delete(s.days, date)
+			}
+		}
+	}
+
+	days := make([]*Day, 0, len(s.days))
+	for _, d := range s.days {
+		days = append(days, d)
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Date < days[j].Date })
+	data, err := json.Marshal(days)
+	s.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to marshal statistics: %v", err)
+	}
+
+	// Write to a temporary file first so a crash never truncates the history
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write statistics: %v", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to write statistics: %v", err)
+	}
+	return nil
+}
+
+func copyDay(d *Day) Day {
+	c := *d
+	c.Buttons = make(map[string]*ButtonDay, len(d.Buttons))
+	for key, b := range d.Buttons {
+		bc := *b
+		bc.Blocked = make(map[string]int, len(b.Blocked))
+		for reason, n := range b.Blocked {
+			bc.Blocked[reason] = n
+		}
+		c.Buttons[key] = &bc
+	}
+	return c
+}
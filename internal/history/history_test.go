@@ -0,0 +1,133 @@
+package history
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"click-guardian/internal/filter"
+)
+
+// statsOf returns statistics with allowed and rapid-down blocked presses of
+// the left button
+func statsOf(allowed, blocked int, intervals ...time.Duration) filter.Stats {
+	s := filter.NewStats()
+	if allowed > 0 {
+		s.Allowed[filter.ButtonLeft] = allowed
+	}
+	if blocked > 0 {
+		s.Blocked[filter.ButtonLeft] = map[filter.Reason]int{filter.ReasonRapidDown: blocked}
+	}
+	if len(intervals) > 0 {
+		var h filter.Histogram
+		for _, d := range intervals {
+			h.Observe(d)
+		}
+		s.Intervals[filter.ButtonLeft] = h
+	}
+	return s
+}
+
+func TestStoreAddSaveOpen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stats.json")
+	store, err := Open(path, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	today := time.Now()
+	yesterday := today.AddDate(0, 0, -1)
+	store.Add(statsOf(10, 2, 300*time.Millisecond, 40*time.Millisecond), yesterday)
+	store.Add(statsOf(5, 1), today)
+	store.Add(statsOf(3, 0, 500*time.Millisecond), today)
+	if err := store.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := Open(path, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	days := reopened.Days(0)
+	if len(days) != 2 {
+		t.Fatalf("got %d days, want 2", len(days))
+	}
+	if days[0].Date != yesterday.Format(dateLayout) || days[1].Date != today.Format(dateLayout) {
+		t.Errorf("dates = %s, %s; want yesterday then today", days[0].Date, days[1].Date)
+	}
+
+	left := days[1].Buttons["left"]
+	if left == nil {
+		t.Fatal("no left button today")
+	}
+	if left.Allowed != 8 || left.BlockedClicks() != 1 || left.Intervals.Count != 1 {
+		t.Errorf("today: %d allowed, %d blocked, %d intervals; want 8, 1, 1",
+			left.Allowed, left.BlockedClicks(), left.Intervals.Count)
+	}
+	if got := days[1].BlockedByHour[today.Hour()]; got != 1 {
+		t.Errorf("blocked in hour %d = %d, want 1", today.Hour(), got)
+	}
+	if got := len(reopened.Days(1)); got != 1 {
+		t.Errorf("Days(1) returned %d days, want today only", got)
+	}
+}
+
+func TestStoreRetention(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stats.json")
+	store, err := Open(path, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store.Add(statsOf(1, 0), time.Now().AddDate(0, 0, -30))
+	store.Add(statsOf(1, 0), time.Now())
+	if err := store.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(store.Days(0)); got != 1 {
+		t.Errorf("kept %d days, want the one inside the retention", got)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	prev := statsOf(10, 4, 300*time.Millisecond)
+	cur := statsOf(15, 4, 300*time.Millisecond, 40*time.Millisecond)
+
+	delta := diff(prev, cur)
+	if delta.Allowed[filter.ButtonLeft] != 5 {
+		t.Errorf("allowed delta = %d, want 5", delta.Allowed[filter.ButtonLeft])
+	}
+	if _, ok := delta.Blocked[filter.ButtonLeft]; ok {
+		t.Errorf("unchanged blocked counts in delta: %v", delta.Blocked)
+	}
+	if h := delta.Intervals[filter.ButtonLeft]; h.Count != 1 || h.Sum != 40*time.Millisecond {
+		t.Errorf("interval delta = %d totalling %v, want one of 40ms", h.Count, h.Sum)
+	}
+
+	// Restarted protection counts from zero again
+	if delta := diff(cur, statsOf(3, 1)); delta.Allowed[filter.ButtonLeft] != 3 || delta.BlockedClicks(filter.ButtonLeft) != 1 {
+		t.Errorf("delta after a reset = %d allowed, %d blocked; want 3 and 1",
+			delta.Allowed[filter.ButtonLeft], delta.BlockedClicks(filter.ButtonLeft))
+	}
+
+	// A reset shows in one counter only, the others may have grown past
+	// their previous values since
+	reset := statsOf(20, 0, 300*time.Millisecond, 40*time.Millisecond, 40*time.Millisecond)
+	delta = diff(cur, reset)
+	if delta.Allowed[filter.ButtonLeft] != 20 || delta.Intervals[filter.ButtonLeft].Count != 3 {
+		t.Errorf("delta after a reset of the blocked count = %d allowed, %d intervals; want all 20 and 3",
+			delta.Allowed[filter.ButtonLeft], delta.Intervals[filter.ButtonLeft].Count)
+	}
+	wheel := statsOf(15, 4)
+	wheel.WheelAllowed = 30
+	grown := statsOf(16, 5)
+	grown.WheelAllowed = 2
+	if delta := diff(wheel, grown); delta.Allowed[filter.ButtonLeft] != 16 || delta.WheelAllowed != 2 {
+		t.Errorf("delta after a reset of the wheel count = %d allowed, %d wheel ticks; want 16 and 2",
+			delta.Allowed[filter.ButtonLeft], delta.WheelAllowed)
+	}
+
+	if !isEmpty(diff(cur, cur)) {
+		t.Error("diff of equal statistics is not empty")
+	}
+}
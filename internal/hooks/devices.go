@@ -0,0 +1,25 @@
+package hooks
+
+import (
+	"fmt"
+)
+
+// Device identifies a physical pointing device
+type Device struct {
+	ID      string // Vendor and product ID, see DeviceID
+	Key     string // Tells attached devices apart: the evdev path on Linux, the raw input handle on Windows
+	Name    string
+	Vendor  uint16
+	Product uint16
+}
+
+// DeviceID returns the ID a device is configured under, e.g. "046d:c077".
+// Identical devices share an ID and therefore their settings.
+func DeviceID(vendor, product uint16) string {
+	return fmt.Sprintf("%04x:%04x", vendor, product)
+}
+
+// String returns a short description for logs
+func (d Device) String() string {
+	return fmt.Sprintf("%s [%s]", d.Name, d.ID)
+}
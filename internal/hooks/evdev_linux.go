@@ -0,0 +1,465 @@
+//go:build linux
+
+package hooks
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// Input event types and codes from linux/input-event-codes.h
+const (
+	evSyn = 0x00
+	evKey = 0x01
+	evRel = 0x02
+	evLed = 0x11
+
+	synReport  = 0x00
+	synDropped = 0x03
+
+	btnLeft   = 0x110
+	btnRight  = 0x111
+	btnMiddle = 0x112
+	btnSide   = 0x113
+	btnExtra  = 0x114
+	btnTask   = 0x117
+
+	keyEsc      = 0x01
+	keyA        = 0x1e
+	keyZ        = 0x2c
+	keyMaxBasic = 0xff  // Last key below the button range
+	keyOk       = 0x160 // First key above the button range
+	keyLast     = 0x2bf // Last key below the joystick trigger buttons
+
+	ledNumLock    = 0x00
+	ledScrollLock = 0x02
+
+	relX           = 0x00
+	relY           = 0x01
+	relHWheel      = 0x06
+	relWheel       = 0x08
+	relWheelHiRes  = 0x0b
+	relHWheelHiRes = 0x0c
+)
+
+// ioctl request numbers from linux/input.h and linux/uinput.h
+const (
+	iocWrite = 1
+	iocRead  = 2
+
+	uinputSetupSize = 92 // struct uinput_setup
+
+	uiDevCreate  = 0x5501
+	uiDevDestroy = 0x5502
+	uiDevSetup   = iocWrite<<30 | uinputSetupSize<<16 | 'U'<<8 | 3
+	uiSetEvBit   = 0x40045564
+	uiSetKeyBit  = 0x40045565
+	uiSetRelBit  = 0x40045566
+	uiSetLedBit  = 0x40045569
+	eviocGrab    = 0x40044590
+)
+
+// Names of the uinput devices we re-emit events on. Devices with these
+// names are skipped during discovery.
+const (
+	virtualDeviceName   = "Click Guardian Virtual Mouse"
+	virtualKeyboardName = "Click Guardian Virtual Keyboard"
+)
+
+// inputEvent mirrors struct input_event
+type inputEvent struct {
+	Time  unix.Timeval
+	Type  uint16
+	Code  uint16
+	Value int32
+}
+
+const inputEventSize = int(unsafe.Sizeof(inputEvent{}))
+
+// inputID mirrors struct input_id
+type inputID struct {
+	Bustype uint16
+	Vendor  uint16
+	Product uint16
+	Version uint16
+}
+
+// uinputSetup mirrors struct uinput_setup
+type uinputSetup struct {
+	ID           inputID
+	Name         [80]byte
+	FFEffectsMax uint32
+}
+
+func eviocgbit(ev, length int) uintptr {
+	return uintptr(iocRead<<30 | length<<16 | 'E'<<8 | (0x20 + ev))
+}
+
+func eviocgid() uintptr {
+	return uintptr(iocRead<<30 | int(unsafe.Sizeof(inputID{}))<<16 | 'E'<<8 | 0x02)
+}
+
+func eviocgkey(length int) uintptr {
+	return uintptr(iocRead<<30 | length<<16 | 'E'<<8 | 0x18)
+}
+
+func eviocgname(length int) uintptr {
+	return uintptr(iocRead<<30 | length<<16 | 'E'<<8 | 0x06)
+}
+
+// ioctl runs an ioctl on the file without switching it to blocking mode
+func ioctl(f *os.File, req uintptr, arg uintptr) error {
+	conn, err := f.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var errno unix.Errno
+	err = conn.Control(func(fd uintptr) {
+		_, _, errno = unix.Syscall(unix.SYS_IOCTL, fd, req, arg)
+	})
+	if err != nil {
+		return err
+	}
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// testBit reports whether bit n is set in an evdev capability bitmap
+func testBit(bits []byte, n int) bool {
+	return n/8 < len(bits) && bits[n/8]&(1<<(n%8)) != 0
+}
+
+// evdevDevice is an input device opened for reading
+type evdevDevice struct {
+	path string
+	name string
+	id   inputID
+	file *os.File
+}
+
+// openEvdevDevice opens path with flag and reads its name and ID
+func openEvdevDevice(path string, flag int) (*evdevDevice, error) {
+	f, err := os.OpenFile(path, flag, 0)
+	if err != nil {
+		return nil, err
+	}
+	name := make([]byte, 256)
+	if err := ioctl(f, eviocgname(len(name)), uintptr(unsafe.Pointer(&name[0]))); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to read device name of %s: %v", path, err)
+	}
+	var id inputID
+	if err := ioctl(f, eviocgid(), uintptr(unsafe.Pointer(&id))); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to read device ID of %s: %v", path, err)
+	}
+	return &evdevDevice{
+		path: path,
+		name: string(bytes.TrimRight(name, "\x00")),
+		id:   id,
+		file: f,
+	}, nil
+}
+
+// device describes the device for settings and events
+func (d *evdevDevice) device() Device {
+	return Device{
+		ID:      DeviceID(d.id.Vendor, d.id.Product),
+		Key:     d.path,
+		Name:    d.name,
+		Vendor:  d.id.Vendor,
+		Product: d.id.Product,
+	}
+}
+
+// isMouse reports whether the device has relative axes and a left button
+func (d *evdevDevice) isMouse() bool {
+	keyBits := make([]byte, (btnTask/8)+1)
+	relBits := make([]byte, 2)
+	if ioctl(d.file, eviocgbit(evKey, len(keyBits)), uintptr(unsafe.Pointer(&keyBits[0]))) != nil {
+		return false
+	}
+	if ioctl(d.file, eviocgbit(evRel, len(relBits)), uintptr(unsafe.Pointer(&relBits[0]))) != nil {
+		return false
+	}
+	return testBit(keyBits, btnLeft) && testBit(relBits, relX) && testBit(relBits, relY)
+}
+
+// isKeyboard reports whether the device has letter keys and is not a mouse,
+// which the mouse hook grabs
+func (d *evdevDevice) isKeyboard() bool {
+	keyBits := make([]byte, (keyZ/8)+1)
+	if ioctl(d.file, eviocgbit(evKey, len(keyBits)), uintptr(unsafe.Pointer(&keyBits[0]))) != nil {
+		return false
+	}
+	return testBit(keyBits, keyEsc) && testBit(keyBits, keyA) && testBit(keyBits, keyZ) && !d.isMouse()
+}
+
+// keysDown reports whether any key of the device is held
+func (d *evdevDevice) keysDown() bool {
+	state := make([]byte, (keyLast/8)+1)
+	if ioctl(d.file, eviocgkey(len(state)), uintptr(unsafe.Pointer(&state[0]))) != nil {
+		return false
+	}
+	for _, b := range state {
+		if b != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// grab takes exclusive access so events only reach us
+func (d *evdevDevice) grab() error {
+	return ioctl(d.file, eviocGrab, 1)
+}
+
+// readEvents reads as many complete events as fit in buf
+func (d *evdevDevice) readEvents(buf []byte) ([]inputEvent, error) {
+	n, err := d.file.Read(buf)
+	if err != nil {
+		return nil, err
+	}
+	return decodeEvents(buf[:n]), nil
+}
+
+// decodeEvents converts raw input_event structs
+func decodeEvents(buf []byte) []inputEvent {
+	events := make([]inputEvent, len(buf)/inputEventSize)
+	for i := range events {
+		events[i] = *(*inputEvent)(unsafe.Pointer(&buf[i*inputEventSize]))
+	}
+	return events
+}
+
+func (d *evdevDevice) close() error {
+	// Closing the file releases the grab as well
+	return d.file.Close()
+}
+
+// write sends events to the device, e.g. to switch its LEDs
+func (d *evdevDevice) write(events []inputEvent) error {
+	buf := make([]byte, len(events)*inputEventSize)
+	for i := range events {
+		*(*inputEvent)(unsafe.Pointer(&buf[i*inputEventSize])) = events[i]
+	}
+	_, err := d.file.Write(buf)
+	return err
+}
+
+// findMice opens every evdev device that looks like a mouse
+func findMice() ([]*evdevDevice, error) {
+	return findDevices("mouse", os.O_RDONLY, (*evdevDevice).isMouse)
+}
+
+// findKeyboards opens every evdev device that looks like a keyboard, for
+// writing as well so their LEDs follow the virtual keyboard
+func findKeyboards() ([]*evdevDevice, error) {
+	return findDevices("keyboard", os.O_RDWR, (*evdevDevice).isKeyboard)
+}
+
+// findDevices opens every evdev device that match accepts
+func findDevices(kind string, flag int, match func(*evdevDevice) bool) ([]*evdevDevice, error) {
+	paths, err := filepath.Glob("/dev/input/event*")
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	var devices []*evdevDevice
+	var lastErr error
+	for _, path := range paths {
+		dev, err := openMatchingDevice(path, flag, match)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if dev != nil {
+			devices = append(devices, dev)
+		}
+	}
+
+	if len(devices) == 0 {
+		if lastErr != nil {
+			return nil, fmt.Errorf("no usable %s found in /dev/input: %w", kind, lastErr)
+		}
+		return nil, fmt.Errorf("no %s found in /dev/input", kind)
+	}
+	return devices, nil
+}
+
+// openMatchingDevice opens path if match accepts it and it is not one of
+// our virtual devices. It returns nil for any other device.
+func openMatchingDevice(path string, flag int, match func(*evdevDevice) bool) (*evdevDevice, error) {
+	dev, err := openEvdevDevice(path, flag)
+	if err != nil {
+		return nil, err
+	}
+	if dev.name == virtualDeviceName || dev.name == virtualKeyboardName || !match(dev) {
+		dev.close()
+		return nil, nil
+	}
+	return dev, nil
+}
+
+// deviceWatcher reports the evdev devices that appear in /dev/input
+type deviceWatcher struct {
+	file *os.File
+}
+
+// watchDevices starts watching /dev/input for new event devices
+func watchDevices() (*deviceWatcher, error) {
+	// Non-blocking so that closing the file wakes a pending read
+	fd, err := unix.InotifyInit1(unix.IN_NONBLOCK | unix.IN_CLOEXEC)
+	if err != nil {
+		return nil, fmt.Errorf("failed to watch /dev/input: %v", err)
+	}
+	// udev creates the node before it sets the permissions that let us open it
+	if _, err := unix.InotifyAddWatch(fd, "/dev/input", unix.IN_CREATE|unix.IN_ATTRIB); err != nil {
+		unix.Close(fd)
+		return nil, fmt.Errorf("failed to watch /dev/input: %v", err)
+	}
+	return &deviceWatcher{file: os.NewFile(uintptr(fd), "/dev/input")}, nil
+}
+
+// read waits for changes and returns the paths of the event devices that
+// were created or changed permissions
+func (w *deviceWatcher) read(buf []byte) ([]string, error) {
+	n, err := w.file.Read(buf)
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for off := 0; off+unix.SizeofInotifyEvent <= n; {
+		ev := (*unix.InotifyEvent)(unsafe.Pointer(&buf[off]))
+		off += unix.SizeofInotifyEvent
+		end := min(off+int(ev.Len), n)
+		name := string(bytes.TrimRight(buf[off:end], "\x00"))
+		off = end
+		if strings.HasPrefix(name, "event") {
+			paths = append(paths, filepath.Join("/dev/input", name))
+		}
+	}
+	return paths, nil
+}
+
+func (w *deviceWatcher) close() error {
+	return w.file.Close()
+}
+
+// uinputDevice is a virtual device that receives the filtered events
+type uinputDevice struct {
+	file *os.File
+}
+
+// uinputBits lists the capabilities of a virtual device per ioctl
+type uinputBits map[uintptr][]int
+
+// createUinputDevice creates a virtual mouse with every button and axis a
+// grabbed device could report
+func createUinputDevice() (*uinputDevice, error) {
+	bits := uinputBits{
+		uiSetEvBit:  {evSyn, evKey, evRel},
+		uiSetRelBit: {relX, relY, relHWheel, relWheel, relWheelHiRes, relHWheelHiRes},
+	}
+	for code := btnLeft; code <= btnTask; code++ {
+		bits[uiSetKeyBit] = append(bits[uiSetKeyBit], code)
+	}
+	u, err := createVirtualDevice(virtualDeviceName, 0xc11c, bits)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up virtual mouse: %v", err)
+	}
+	return u, nil
+}
+
+// createUinputKeyboard creates a virtual keyboard with every key and LED a
+// grabbed keyboard could report
+func createUinputKeyboard() (*uinputDevice, error) {
+	bits := uinputBits{uiSetEvBit: {evSyn, evKey, evLed}}
+	for code := keyEsc; code <= keyLast; code++ {
+		if code <= keyMaxBasic || code >= keyOk {
+			bits[uiSetKeyBit] = append(bits[uiSetKeyBit], code)
+		}
+	}
+	for code := ledNumLock; code <= ledScrollLock; code++ {
+		bits[uiSetLedBit] = append(bits[uiSetLedBit], code)
+	}
+	u, err := createVirtualDevice(virtualKeyboardName, 0xc11d, bits)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up virtual keyboard: %v", err)
+	}
+	return u, nil
+}
+
+// createVirtualDevice creates a uinput device with the given capabilities.
+// It is opened for reading as well, to receive LED changes.
+func createVirtualDevice(name string, product uint16, bits uinputBits) (*uinputDevice, error) {
+	f, err := os.OpenFile("/dev/uinput", os.O_RDWR, 0)
+	if err != nil {
+		return nil, err
+	}
+	u := &uinputDevice{file: f}
+
+	setup := func() error {
+		// Event types first, the other bits depend on them
+		for _, req := range []uintptr{uiSetEvBit, uiSetKeyBit, uiSetRelBit, uiSetLedBit} {
+			for _, code := range bits[req] {
+				if err := ioctl(f, req, uintptr(code)); err != nil {
+					return err
+				}
+			}
+		}
+
+		var s uinputSetup
+		s.ID = inputID{Bustype: 0x06, Vendor: 0x1209, Product: product, Version: 1} // BUS_VIRTUAL
+		copy(s.Name[:], name)
+		if err := ioctl(f, uiDevSetup, uintptr(unsafe.Pointer(&s))); err != nil {
+			return err
+		}
+		return ioctl(f, uiDevCreate, 0)
+	}
+
+	if err := setup(); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return u, nil
+}
+
+// read returns the events applications sent to the virtual device, such as
+// LED changes
+func (u *uinputDevice) read(buf []byte) ([]inputEvent, error) {
+	n, err := u.file.Read(buf)
+	if err != nil {
+		return nil, err
+	}
+	return decodeEvents(buf[:n]), nil
+}
+
+// write emits events followed by a SYN_REPORT
+func (u *uinputDevice) write(events []inputEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	events = append(events, inputEvent{Type: evSyn, Code: synReport})
+	buf := make([]byte, len(events)*inputEventSize)
+	for i := range events {
+		*(*inputEvent)(unsafe.Pointer(&buf[i*inputEventSize])) = events[i]
+	}
+	_, err := u.file.Write(buf)
+	return err
+}
+
+func (u *uinputDevice) close() error {
+	ioctl(u.file, uiDevDestroy, 0)
+	return u.file.Close()
+}
@@ -0,0 +1,198 @@
+package hooks
+
+import (
+	"time"
+
+	"click-guardian/internal/filter"
+)
+
+// EventType describes what a hook Event reports
+type EventType int
+
+const (
+	EventButton        EventType = iota // A button press or release was allowed or blocked
+	EventDrag                           // A drag operation started
+	EventWheel                          // A scroll wheel tick was allowed or blocked
+	EventDelayAdjusted                  // The adaptive delay of a button changed
+	EventHookInstalled                  // Protection became active
+	EventHookRemoved                    // Protection stopped
+	EventDeviceGrabbed                  // An input device was taken over by the hook
+	EventError                          // The hook failed or lost a device
+	EventKey                            // A key press was blocked as chatter
+	EventHotkey                         // The pause hotkey was pressed
+)
+
+// String returns the name used for the event type in logs and the control API
+func (t EventType) String() string {
+	switch t {
+	case EventButton:
+		return "button"
+	case EventDrag:
+		return "drag"
+	case EventWheel:
+		return "wheel"
+	case EventDelayAdjusted:
+		return "delay-adjusted"
+	case EventHookInstalled:
+		return "hook-installed"
+	case EventHookRemoved:
+		return "hook-removed"
+	case EventDeviceGrabbed:
+		return "device-grabbed"
+	case EventError:
+		return "error"
+	case EventKey:
+		return "key"
+	case EventHotkey:
+		return "hotkey"
+	default:
+		return "unknown"
+	}
+}
+
+// Event is a structured notification published by a MouseHook
+type Event struct {
+	Type   EventType
+	Time   time.Time
+	Button filter.Button
+	Kind   filter.Kind // KindDown or KindUp for EventButton
+	Delta  int         // Wheel rotation for EventWheel
+	Key    string      // Key name for EventKey, the hotkey for EventHotkey
+
+	Allowed  bool
+	Reason   filter.Reason
+	Interval time.Duration // Gap that triggered a block, or hold time of an allowed release
+	Hold     time.Duration
+	Drag     bool
+
+	// DoubleClick is set on an allowed press that only passed as an
+	// intentional double-click; Interval then holds the release-to-press gap
+	DoubleClick bool
+
+	Delay     time.Duration // Effective delay applied to the button, the wheel window or the key threshold
+	BaseDelay time.Duration // User-selected delay
+
+	// ShortClicks is the number of very short holds among the recent clicks
+	// when an allowed release was one
+	ShortClicks int
+
+	// Device names the device for EventDeviceGrabbed and EventError, and
+	// the device that produced a button or wheel event when it is known
+	Device   string
+	DeviceID string // ID of Device, see DeviceID
+	Err      error  // Cause of an EventError
+
+	// Fatal is set on an EventError after which the hook no longer filters
+	// anything, as opposed to losing a device or an optional feature
+	Fatal bool
+}
+
+// reporter turns filter decisions into events and keeps the statistics
+type reporter struct {
+	config   filter.Config
+	events   chan<- Event
+	stats    filter.Stats
+	observer filter.Observer
+}
+
+// publish sends an event without ever blocking the hook
+func (r *reporter) publish(ev Event) {
+	send(r.events, ev)
+}
+
+// send publishes an event on events unless the channel is full or nil
+func send(events chan<- Event, ev Event) {
+	if events == nil {
+		return
+	}
+	if ev.Time.IsZero() {
+		ev.Time = time.Now()
+	}
+	select {
+	case events <- ev:
+	default:
+		// Event channel is full, the event is dropped to prevent blocking.
+	}
+}
+
+// snapshot copies the statistics and adds the effective delays of engines
+func (r *reporter) snapshot(engines *filter.EngineSet) filter.Stats {
+	s := r.stats.Clone()
+	if engines != nil {
+		for _, button := range filter.Buttons() {
+			if delay := engines.EffectiveDelay(button); delay > 0 {
+				s.Delays[button] = delay
+			}
+		}
+	}
+	return s
+}
+
+// observeBypassed shows an event that skipped the filter to the observer,
+// so that traces are complete
+func (r *reporter) observeBypassed(ev filter.Event) {
+	if r.observer != nil {
+		r.observer(ev, filter.Decision{Allow: true, Bypassed: true})
+	}
+}
+
+// report updates the statistics and publishes the decision dev took
+func (r *reporter) report(dev Device, ev filter.Event, d filter.Decision) {
+	r.stats.Record(ev, d)
+	if r.observer != nil {
+		r.observer(ev, d)
+	}
+
+	switch ev.Kind {
+	case filter.KindMove:
+		for _, button := range d.DragStarted {
+			r.publish(Event{Type: EventDrag, Time: ev.Time, Button: button})
+		}
+		return
+	case filter.KindWheel:
+		r.publish(Event{
+			Type:     EventWheel,
+			Time:     ev.Time,
+			Delta:    ev.Delta,
+			Allowed:  d.Allow,
+			Reason:   d.Reason,
+			Interval: d.Interval,
+			Delay:    d.Delay,
+			Device:   dev.Name,
+			DeviceID: dev.ID,
+		})
+		return
+	}
+
+	baseDelay := r.config.ForDevice(dev.ID).DelayFor(ev.Button)
+
+	r.publish(Event{
+		Type:        EventButton,
+		Time:        ev.Time,
+		Button:      ev.Button,
+		Kind:        ev.Kind,
+		Allowed:     d.Allow,
+		Reason:      d.Reason,
+		Interval:    d.Interval,
+		Hold:        d.Hold,
+		Drag:        d.Drag,
+		DoubleClick: d.DoubleClick,
+		Delay:       d.Delay,
+		BaseDelay:   baseDelay,
+		ShortClicks: d.ShortClicks,
+		Device:      dev.Name,
+		DeviceID:    dev.ID,
+	})
+
+	if d.Adjusted {
+		r.publish(Event{
+			Type:      EventDelayAdjusted,
+			Time:      ev.Time,
+			Button:    ev.Button,
+			Delay:     d.AdaptiveDelay,
+			BaseDelay: baseDelay,
+			Device:    dev.Name,
+			DeviceID:  dev.ID,
+		})
+	}
+}
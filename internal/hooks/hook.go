@@ -3,15 +3,19 @@
 package hooks
 
 import (
-	"time"
+	"click-guardian/internal/filter"
 )
 
 // MouseHook defines the interface for mouse hooking functionality
 type MouseHook interface {
-	Start(delay time.Duration, logChan chan string) error
+	Start(cfg filter.Config, events chan<- Event) error
 	Stop() error
+	SetConfig(cfg filter.Config)
 	GetBlockedCount() int
 	ResetBlockedCount()
+	Stats() filter.Stats
+	SetObserver(observer filter.Observer)
+	Devices() ([]Device, error)
 	IsSupported() bool
 }
 
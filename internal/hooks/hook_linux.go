@@ -0,0 +1,380 @@
+//go:build linux
+
+package hooks
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"click-guardian/internal/filter"
+)
+
+// linuxHook grabs every mouse through evdev, filters button chatter and
+// re-emits the cleaned events through a uinput virtual mouse
+type linuxHook struct {
+	reporter
+	mu        sync.Mutex
+	engines   *filter.EngineSet
+	bypass    bypassGate
+	devices   []*evdevDevice
+	output    *uinputDevice
+	watcher   *deviceWatcher // Reports mice plugged in while running
+	wg        sync.WaitGroup
+	isRunning bool
+}
+
+func newPlatformHook() MouseHook {
+	return &linuxHook{}
+}
+
+// PerDeviceFiltering reports whether clicks are filtered with the settings
+// of the mouse that made them. Every evdev device is read on its own.
+const PerDeviceFiltering = true
+
+// linuxButtons maps evdev button codes to filter buttons
+var linuxButtons = map[uint16]filter.Button{
+	btnLeft:   filter.ButtonLeft,
+	btnRight:  filter.ButtonRight,
+	btnMiddle: filter.ButtonMiddle,
+	btnSide:   filter.ButtonX1,
+	btnExtra:  filter.ButtonX2,
+}
+
+func (l *linuxHook) Start(cfg filter.Config, events chan<- Event) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.isRunning {
+		return fmt.Errorf("hook is already running")
+	}
+
+	output, err := createUinputDevice()
+	if err != nil {
+		return permissionHint(fmt.Errorf("failed to open /dev/uinput: %w", err))
+	}
+
+	devices, err := findMice()
+	if err != nil {
+		output.close()
+		return permissionHint(err)
+	}
+
+	for i, dev := range devices {
+		if err := dev.grab(); err != nil {
+			for _, d := range devices {
+				d.close()
+			}
+			output.close()
+			return fmt.Errorf("failed to grab %s (%s): %v", devices[i].name, devices[i].path, err)
+		}
+	}
+
+	l.config = cfg
+	l.events = events
+	l.engines = filter.NewEngineSet(cfg)
+	l.output = output
+	l.devices = devices
+	l.isRunning = true
+
+	for _, dev := range devices {
+		l.publish(Event{Type: EventDeviceGrabbed, Device: fmt.Sprintf("%s (%s)", dev.device(), dev.path), DeviceID: dev.device().ID})
+		l.wg.Add(1)
+		go l.readLoop(dev, output)
+	}
+
+	// Without the watcher protection still covers the mice found now
+	if watcher, err := watchDevices(); err != nil {
+		l.publish(Event{Type: EventError, Err: err})
+	} else {
+		l.watcher = watcher
+		l.wg.Add(1)
+		go l.watchLoop(watcher, output)
+	}
+	l.publish(Event{Type: EventHookInstalled})
+
+	return nil
+}
+
+// readLoop filters one device into output until the device is closed
+func (l *linuxHook) readLoop(dev *evdevDevice, output *uinputDevice) {
+	defer l.wg.Done()
+
+	device := dev.device()
+	buf := make([]byte, 64*inputEventSize)
+	var frame []inputEvent
+	for {
+		events, err := dev.readEvents(buf)
+		if err != nil {
+			if !errors.Is(err, os.ErrClosed) {
+				l.publish(Event{Type: EventError, Device: dev.name, Err: err})
+				l.detach(dev)
+			}
+			return
+		}
+		for _, ev := range events {
+			if ev.Type != evSyn {
+				frame = append(frame, ev)
+				continue
+			}
+			if ev.Code == synReport {
+				l.emit(output, l.filterFrame(device, frame))
+			}
+			// SYN_DROPPED and other sync events discard the partial frame
+			frame = frame[:0]
+		}
+	}
+}
+
+// watchLoop grabs the mice plugged in while the hook runs
+func (l *linuxHook) watchLoop(watcher *deviceWatcher, output *uinputDevice) {
+	defer l.wg.Done()
+
+	buf := make([]byte, 4096)
+	for {
+		paths, err := watcher.read(buf)
+		if err != nil {
+			if !errors.Is(err, os.ErrClosed) {
+				l.publish(Event{Type: EventError, Err: err})
+			}
+			return
+		}
+		for _, path := range paths {
+			l.attach(path, output)
+		}
+	}
+}
+
+// attach grabs the device at path if it is a mouse that is not grabbed yet
+func (l *linuxHook) attach(path string, output *uinputDevice) {
+	// Opening fails until udev has set the permissions, the change that
+	// follows retries
+	dev, err := openMatchingDevice(path, os.O_RDONLY, (*evdevDevice).isMouse)
+	if err != nil || dev == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.isRunning || slices.ContainsFunc(l.devices, func(d *evdevDevice) bool { return d.path == path }) {
+		dev.close()
+		return
+	}
+	if err := dev.grab(); err != nil {
+		dev.close()
+		l.publish(Event{Type: EventError, Device: dev.name, Err: fmt.Errorf("failed to grab %s (%s): %v", dev.name, dev.path, err)})
+		return
+	}
+	l.devices = append(l.devices, dev)
+	l.publish(Event{Type: EventDeviceGrabbed, Device: fmt.Sprintf("%s (%s)", dev.device(), dev.path), DeviceID: dev.device().ID})
+	l.wg.Add(1)
+	go l.readLoop(dev, output)
+}
+
+// detach releases a device that failed, usually because it was unplugged,
+// so that it is grabbed again when it comes back
+func (l *linuxHook) detach(dev *evdevDevice) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if i := slices.Index(l.devices, dev); i >= 0 {
+		l.devices = slices.Delete(l.devices, i, i+1)
+		dev.close()
+	}
+}
+
+// filterFrame runs the button events of one report through the engine of
+// its device and returns the events that should be re-emitted
+func (l *linuxHook) filterFrame(dev Device, frame []inputEvent) []inputEvent {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.isRunning {
+		return nil
+	}
+
+	out := make([]inputEvent, 0, len(frame))
+	var moved time.Time
+	wheelBlocked := false
+	for _, ev := range frame {
+		if ev.Type == evRel && (ev.Code == relX || ev.Code == relY) && ev.Value != 0 {
+			moved = eventTime(ev)
+		}
+		if ev.Type == evRel && ev.Code == relWheel && ev.Value != 0 {
+			fev := filter.Event{
+				Kind:      filter.KindWheel,
+				Delta:     int(ev.Value),
+				Time:      eventTime(ev),
+				DeviceKey: dev.Key,
+				DeviceID:  dev.ID,
+			}
+			if l.bypass.pass(fev) {
+				l.observeBypassed(fev)
+			} else {
+				d := l.engines.Process(fev)
+				l.report(dev, fev, d)
+				wheelBlocked = !d.Allow
+			}
+		}
+
+		button, ok := linuxButtons[ev.Code]
+		if ev.Type != evKey || !ok || ev.Value == 2 {
+			out = append(out, ev)
+			continue
+		}
+
+		fev := filter.Event{
+			Kind:      filter.KindUp,
+			Button:    button,
+			Time:      eventTime(ev),
+			DeviceKey: dev.Key,
+			DeviceID:  dev.ID,
+		}
+		if ev.Value == 1 {
+			fev.Kind = filter.KindDown
+		}
+		if l.bypass.pass(fev) {
+			l.observeBypassed(fev)
+			out = append(out, ev)
+			continue
+		}
+		d := l.engines.Process(fev)
+		l.report(dev, fev, d)
+		if d.Allow {
+			out = append(out, ev)
+		}
+	}
+
+	if wheelBlocked {
+		// Drop the whole notch, including its high-resolution counterpart
+		kept := out[:0]
+		for _, ev := range out {
+			if ev.Type != evRel || (ev.Code != relWheel && ev.Code != relWheelHiRes) {
+				kept = append(kept, ev)
+			}
+		}
+		out = kept
+	}
+
+	if !moved.IsZero() {
+		fev := filter.Event{Kind: filter.KindMove, Time: moved, DeviceKey: dev.Key, DeviceID: dev.ID}
+		l.report(dev, fev, l.engines.Process(fev))
+	}
+	return out
+}
+
+// eventTime returns the time the kernel stamped an event with
+func eventTime(ev inputEvent) time.Time {
+	return time.Unix(ev.Time.Unix())
+}
+
+func (l *linuxHook) emit(output *uinputDevice, events []inputEvent) {
+	if err := output.write(events); err != nil && !errors.Is(err, os.ErrClosed) {
+		l.publish(Event{Type: EventError, Device: virtualDeviceName, Err: err})
+	}
+}
+
+func (l *linuxHook) Stop() error {
+	l.mu.Lock()
+	if !l.isRunning {
+		l.mu.Unlock()
+		return nil
+	}
+	l.isRunning = false
+	if l.watcher != nil {
+		l.watcher.close()
+		l.watcher = nil
+	}
+	for _, dev := range l.devices {
+		dev.close()
+	}
+	l.devices = nil
+	output := l.output
+	l.output = nil
+	l.mu.Unlock()
+
+	// Wait for the readers before tearing down the virtual mouse
+	l.wg.Wait()
+	output.close()
+
+	l.publish(Event{Type: EventHookRemoved})
+	return nil
+}
+
+func (l *linuxHook) GetBlockedCount() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.stats.TotalBlockedClicks()
+}
+
+// ResetBlockedCount clears all statistics
+func (l *linuxHook) ResetBlockedCount() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.stats = filter.NewStats()
+}
+
+func (l *linuxHook) Stats() filter.Stats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.snapshot(l.engines)
+}
+
+// SetConfig switches the settings of a running hook
+func (l *linuxHook) SetConfig(cfg filter.Config) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.isRunning {
+		return
+	}
+	l.config = cfg
+	l.engines.SetConfig(cfg)
+}
+
+// SetObserver installs a callback that sees every processed event
+func (l *linuxHook) SetObserver(observer filter.Observer) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.observer = observer
+}
+
+// Devices lists the grabbed mice, or the mice that would be grabbed when
+// the hook is not running
+func (l *linuxHook) Devices() ([]Device, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	devices := l.devices
+	if !l.isRunning {
+		mice, err := findMice()
+		if err != nil {
+			return nil, permissionHint(err)
+		}
+		for _, dev := range mice {
+			dev.close()
+		}
+		devices = mice
+	}
+
+	list := make([]Device, 0, len(devices))
+	for _, dev := range devices {
+		list = append(list, dev.device())
+	}
+	return list, nil
+}
+
+func (l *linuxHook) IsSupported() bool {
+	return true
+}
+
+// permissionHint adds a hint about device permissions to access errors
+func permissionHint(err error) error {
+	if errors.Is(err, os.ErrPermission) {
+		return fmt.Errorf("%v (add your user to the 'input' group and allow access to /dev/uinput)", err)
+	}
+	return err
+}
@@ -0,0 +1,121 @@
+//go:build linux
+
+package hooks
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/sys/unix"
+
+	"click-guardian/internal/filter"
+)
+
+// frameStart is the kernel time of the first event of a test
+var frameStart = time.Date(2024, 3, 30, 12, 0, 0, 0, time.UTC)
+
+// input returns an evdev event stamped ms after frameStart
+func input(ms int, typ, code uint16, value int32) inputEvent {
+	t := frameStart.Add(time.Duration(ms) * time.Millisecond)
+	return inputEvent{Time: unix.NsecToTimeval(t.UnixNano()), Type: typ, Code: code, Value: value}
+}
+
+// runningHook returns a hook that filters frames with cfg without any
+// device grabbed
+func runningHook(cfg filter.Config) *linuxHook {
+	l := &linuxHook{engines: filter.NewEngineSet(cfg), isRunning: true}
+	l.config = cfg
+	l.stats = filter.NewStats()
+	return l
+}
+
+var (
+	testMouse  = Device{Key: "/dev/input/event3", ID: "046d:c077", Name: "Test Mouse"}
+	otherMouse = Device{Key: "/dev/input/event7", ID: "046d:c077", Name: "Test Mouse"}
+)
+
+func TestFilterFrameDropsBounces(t *testing.T) {
+	l := runningHook(filter.NewConfig(50 * time.Millisecond))
+
+	frames := []struct {
+		frame []inputEvent
+		want  int // Events kept
+	}{
+		{[]inputEvent{input(0, evKey, btnLeft, 1)}, 1},
+		{[]inputEvent{input(80, evKey, btnLeft, 0)}, 1},
+		// The bounce is dropped, the motion reported with it is kept
+		{[]inputEvent{input(100, evRel, relX, 3), input(100, evKey, btnLeft, 1)}, 1},
+		{[]inputEvent{input(110, evKey, btnLeft, 0)}, 0},
+		// Autorepeat and buttons we do not filter pass untouched
+		{[]inputEvent{input(120, evKey, btnLeft, 2), input(120, evKey, btnTask, 1)}, 2},
+	}
+	for i, f := range frames {
+		out := l.filterFrame(testMouse, f.frame)
+		if len(out) != f.want {
+			t.Errorf("frame %d: kept %v, want %d events", i, out, f.want)
+		}
+	}
+	if got := l.stats.TotalBlockedClicks(); got != 1 {
+		t.Errorf("blocked %d clicks, want 1", got)
+	}
+}
+
+func TestFilterFrameSeparatesDevices(t *testing.T) {
+	l := runningHook(filter.NewConfig(50 * time.Millisecond))
+
+	// Two mice of the same model clicking 10ms apart are not bounces
+	l.filterFrame(testMouse, []inputEvent{input(0, evKey, btnLeft, 1)})
+	l.filterFrame(testMouse, []inputEvent{input(80, evKey, btnLeft, 0)})
+	if out := l.filterFrame(otherMouse, []inputEvent{input(90, evKey, btnLeft, 1)}); len(out) != 1 {
+		t.Errorf("press of the other mouse was dropped")
+	}
+	if out := l.filterFrame(testMouse, []inputEvent{input(100, evKey, btnLeft, 1)}); len(out) != 0 {
+		t.Errorf("bounce of the first mouse was kept")
+	}
+}
+
+func TestFilterFrameDropsWholeWheelNotch(t *testing.T) {
+	cfg := filter.NewConfig(50 * time.Millisecond)
+	cfg.Wheel.Enabled = true
+	l := runningHook(cfg)
+
+	notch := func(ms int, dir int32) []inputEvent {
+		return []inputEvent{input(ms, evRel, relWheel, dir), input(ms, evRel, relWheelHiRes, 120*dir)}
+	}
+	for _, ms := range []int{0, 30, 60} {
+		l.filterFrame(testMouse, notch(ms, 1))
+	}
+	if out := l.filterFrame(testMouse, notch(90, -1)); len(out) != 0 {
+		t.Errorf("reversed notch kept %v", out)
+	}
+	if out := l.filterFrame(testMouse, notch(120, 1)); len(out) != 2 {
+		t.Errorf("notch after the reversal kept %v, want both events", out)
+	}
+}
+
+func TestFilterFrameUsesKernelTime(t *testing.T) {
+	l := runningHook(filter.NewConfig(50 * time.Millisecond))
+	var times []time.Time
+	l.observer = func(ev filter.Event, d filter.Decision) {
+		times = append(times, ev.Time)
+	}
+
+	l.filterFrame(testMouse, []inputEvent{input(0, evKey, btnLeft, 1)})
+	l.filterFrame(testMouse, []inputEvent{input(20, evRel, relY, -2)})
+	if len(times) != 2 {
+		t.Fatalf("observed %d events, want a press and a move", len(times))
+	}
+	for i, ms := range []int{0, 20} {
+		if want := frameStart.Add(time.Duration(ms) * time.Millisecond); !times[i].Equal(want) {
+			t.Errorf("event %d at %v, want the kernel time %v", i, times[i], want)
+		}
+	}
+}
+
+func TestFilterFrameAfterStop(t *testing.T) {
+	l := runningHook(filter.NewConfig(50 * time.Millisecond))
+	l.isRunning = false
+	if out := l.filterFrame(testMouse, []inputEvent{input(0, evKey, btnLeft, 1)}); out != nil {
+		t.Errorf("stopped hook re-emitted %v", out)
+	}
+}
@@ -1,10 +1,11 @@
-//go:build !windows
+//go:build !windows && !linux
 
 package hooks
 
 import (
 	"fmt"
-	"time"
+
+	"click-guardian/internal/filter"
 )
 
 type unsupportedHook struct{}
@@ -13,8 +14,11 @@ func newPlatformHook() MouseHook {
 	return &unsupportedHook{}
 }
 
-func (u *unsupportedHook) Start(delay time.Duration, logChan chan string) error {
-	logChan <- "❌ Mouse hooking is only supported on Windows."
+// PerDeviceFiltering reports whether clicks are filtered with the settings
+// of the mouse that made them
+const PerDeviceFiltering = false
+
+func (u *unsupportedHook) Start(cfg filter.Config, events chan<- Event) error {
 	return fmt.Errorf("mouse hooking not supported on this platform")
 }
 
@@ -30,6 +34,22 @@ func (u *unsupportedHook) ResetBlockedCount() {
 	// No-op
 }
 
+func (u *unsupportedHook) Stats() filter.Stats {
+	return filter.NewStats()
+}
+
+func (u *unsupportedHook) SetConfig(cfg filter.Config) {
+	// No-op
+}
+
+func (u *unsupportedHook) SetObserver(observer filter.Observer) {
+	// No-op
+}
+
+func (u *unsupportedHook) Devices() ([]Device, error) {
+	return nil, fmt.Errorf("mouse hooking not supported on this platform")
+}
+
 func (u *unsupportedHook) IsSupported() bool {
 	return false
 }
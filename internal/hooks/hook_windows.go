@@ -20,315 +20,206 @@ import "C"
 
 import (
 	"fmt"
+	"sync"
+	"sync/atomic"
 	"time"
+	"unsafe"
+
+	"click-guardian/internal/filter"
 )
 
 // Windows message constants
 const (
-	WM_LBUTTONUP = 0x0202
-	WM_RBUTTONUP = 0x0205
-	WM_MOUSEMOVE = 0x0200
+	WM_LBUTTONUP   = 0x0202
+	WM_RBUTTONUP   = 0x0205
+	WM_MBUTTONDOWN = 0x0207
+	WM_MBUTTONUP   = 0x0208
+	WM_XBUTTONDOWN = 0x020B
+	WM_XBUTTONUP   = 0x020C
+	WM_MOUSEMOVE   = 0x0200
+	WM_MOUSEWHEEL  = 0x020A
+
+	XBUTTON1 = 0x0001
+	XBUTTON2 = 0x0002
 )
 
+// windowsHook filters clicks with a low-level mouse hook. The hook cannot
+// tell which mouse produced an event, so every click goes through the
+// engine of the zero Device and per-device settings do not apply.
 type windowsHook struct {
-	hook              C.HHOOK
-	delay             time.Duration
-	lastCompleteClick time.Time
-	lastClickButton   C.WPARAM
-	buttonPressed     map[C.WPARAM]bool
-	buttonPressTime   map[C.WPARAM]time.Time
-	dragDetected      map[C.WPARAM]bool
-
-	// Faulty hardware detection
-	faultyClickPattern map[C.WPARAM][]time.Time   // Track recent click times
-	adaptiveDelay      map[C.WPARAM]time.Duration // Per-button adaptive delay
-	shortClickCount    map[C.WPARAM]int           // Count of very short clicks (likely low pressure)
-
-	logChannel   chan string
-	blockedCount int
-	isRunning    bool
-
-	// Track last DOWN and UP event times
-	lastDownTime    map[C.WPARAM]time.Time // Track last DOWN event time
-	lastDownBlocked map[C.WPARAM]bool      // Track if last DOWN was blocked
-	lastUpTime      map[C.WPARAM]time.Time // Track last UP event time
+	reporter
+	mu        sync.Mutex // Guards the engines and statistics
+	engines   *filter.EngineSet
+	bypass    bypassGate
+	thread    *hookThread
+	isRunning atomic.Bool
 }
 
 func newPlatformHook() MouseHook {
 	return &windowsHook{}
 }
 
-var globalHook *windowsHook
+// PerDeviceFiltering reports whether clicks are filtered with the settings
+// of the mouse that made them. The low-level hook only sees the merged
+// input of all mice, and Raw Input, which knows the device, is not
+// guaranteed to arrive before the hook has to decide.
+const PerDeviceFiltering = false
 
-func (w *windowsHook) sendLog(msg string) {
-	select {
-	case w.logChannel <- msg:
+var globalHook atomic.Pointer[windowsHook]
+
+// translateMessage maps a low-level mouse message to a filter event
+func translateMessage(wParam C.WPARAM, lParam C.LPARAM) (filter.Event, bool) {
+	ev := filter.Event{Time: time.Now()}
+	switch wParam {
+	case C.WM_LBUTTONDOWN:
+		ev.Kind, ev.Button = filter.KindDown, filter.ButtonLeft
+	case C.WM_RBUTTONDOWN:
+		ev.Kind, ev.Button = filter.KindDown, filter.ButtonRight
+	case C.WPARAM(WM_LBUTTONUP):
+		ev.Kind, ev.Button = filter.KindUp, filter.ButtonLeft
+	case C.WPARAM(WM_RBUTTONUP):
+		ev.Kind, ev.Button = filter.KindUp, filter.ButtonRight
+	case C.WPARAM(WM_MBUTTONDOWN):
+		ev.Kind, ev.Button = filter.KindDown, filter.ButtonMiddle
+	case C.WPARAM(WM_MBUTTONUP):
+		ev.Kind, ev.Button = filter.KindUp, filter.ButtonMiddle
+	case C.WPARAM(WM_XBUTTONDOWN), C.WPARAM(WM_XBUTTONUP):
+		ev.Kind = filter.KindDown
+		if wParam == C.WPARAM(WM_XBUTTONUP) {
+			ev.Kind = filter.KindUp
+		}
+		// The high word of mouseData tells which side button changed
+		info := (*C.MSLLHOOKSTRUCT)(unsafe.Pointer(uintptr(lParam)))
+		switch (uint32(info.mouseData) >> 16) & 0xFFFF {
+		case XBUTTON1:
+			ev.Button = filter.ButtonX1
+		case XBUTTON2:
+			ev.Button = filter.ButtonX2
+		default:
+			return ev, false
+		}
+	case C.WPARAM(WM_MOUSEMOVE):
+		ev.Kind = filter.KindMove
+	case C.WPARAM(WM_MOUSEWHEEL):
+		// The high word of mouseData holds the signed wheel delta
+		info := (*C.MSLLHOOKSTRUCT)(unsafe.Pointer(uintptr(lParam)))
+		ev.Kind = filter.KindWheel
+		ev.Delta = int(int16(uint32(info.mouseData) >> 16))
 	default:
-		// Log channel is full, message is dropped to prevent blocking.
+		return ev, false
 	}
+	return ev, true
 }
 
 //export LowLevelMouseProc
 func LowLevelMouseProc(nCode C.int, wParam C.WPARAM, lParam C.LPARAM) C.LRESULT {
-	if nCode < 0 || globalHook == nil {
-		return C.CallNextHookEx(globalHook.hook, nCode, wParam, lParam)
+	w := globalHook.Load()
+	if nCode < 0 || w == nil {
+		return callNextHook(nCode, wParam, lParam)
 	}
 
-	// now := time.Now()
-
-	switch wParam {
-	case C.WM_LBUTTONDOWN, C.WM_RBUTTONDOWN:
-		buttonName := "Left"
-		if wParam == C.WM_RBUTTONDOWN {
-			buttonName = "Right"
-		}
-
-		now := time.Now()
-		lastDown := globalHook.lastDownTime[wParam]
-		interval := now.Sub(lastDown)
-		if !lastDown.IsZero() && interval < globalHook.getEffectiveDelay(wParam) {
-			// Always block rapid successive DOWN events (hardware bounce or double-click)
-			globalHook.lastDownBlocked[wParam] = true
-			globalHook.lastDownTime[wParam] = now
-			globalHook.blockedCount++
-			globalHook.sendLog(fmt.Sprintf("🛑 STRICT BLOCK: %s hardware bounce/double-click (%.0fms after previous DOWN, delay: %.0fms) - Total blocked: %d",
-				buttonName, float64(interval.Nanoseconds())/1000000, float64(globalHook.getEffectiveDelay(wParam).Nanoseconds())/1000000, globalHook.blockedCount))
-			return 1
-		}
-		globalHook.lastDownTime[wParam] = now
-		globalHook.lastDownBlocked[wParam] = false
-
-		// Enhance strictness of double-click blocking logic
-		if globalHook.buttonPressed[wParam] {
-			// Strictly block any DOWN events within the delay
-			timeSincePress := now.Sub(globalHook.buttonPressTime[wParam])
-			effectiveDelay := globalHook.getEffectiveDelay(wParam)
-			if timeSincePress < effectiveDelay {
-				globalHook.blockedCount++
-				globalHook.sendLog(fmt.Sprintf("🚫 STRICT BLOCK: %s button hardware bounce (%.0fms since press, delay: %.0fms) - Total blocked: %d",
-					buttonName, float64(timeSincePress.Nanoseconds())/1000000,
-					float64(effectiveDelay.Nanoseconds())/1000000, globalHook.blockedCount))
-				return 1 // Block the bounce
-			}
-		}
-
-		// Strictly block rapid successive complete clicks
-		if !globalHook.lastCompleteClick.IsZero() &&
-			now.Sub(globalHook.lastCompleteClick) < globalHook.getEffectiveDelay(wParam) &&
-			wParam == globalHook.lastClickButton {
-			effectiveDelay := globalHook.getEffectiveDelay(wParam)
-			globalHook.blockedCount++
-			globalHook.sendLog(fmt.Sprintf("🚫 STRICT BLOCK: %s button rapid double-click (%.0fms after complete click, delay: %.0fms) - Total blocked: %d",
-				buttonName, float64(now.Sub(globalHook.lastCompleteClick).Nanoseconds())/1000000,
-				float64(effectiveDelay.Nanoseconds())/1000000, globalHook.blockedCount))
-			return 1 // Block the rapid click
-		}
-
-		// Allow the click and mark button as pressed
-		globalHook.buttonPressed[wParam] = true
-		globalHook.buttonPressTime[wParam] = now
-		globalHook.dragDetected[wParam] = false
-		globalHook.sendLog(fmt.Sprintf("✅ ALLOWED: %s button press", buttonName))
-
-	case C.WPARAM(WM_LBUTTONUP), C.WPARAM(WM_RBUTTONUP):
-		// Determine which button was released
-		var downEvent C.WPARAM
-		buttonName := "Left"
-		if wParam == C.WPARAM(WM_LBUTTONUP) {
-			downEvent = C.WM_LBUTTONDOWN
-		} else {
-			downEvent = C.WM_RBUTTONDOWN
-			buttonName = "Right"
-		}
-
-		now := time.Now()
-		lastDown := globalHook.buttonPressTime[downEvent]
-		lastUp := globalHook.lastUpTime[wParam]
-		upInterval := now.Sub(lastUp)
-		holdDuration := now.Sub(lastDown)
-
-		// Block UP events that occur too quickly after a DOWN event
-		if !globalHook.buttonPressed[downEvent] || (!lastUp.IsZero() && upInterval < globalHook.getEffectiveDelay(downEvent)) {
-			globalHook.sendLog(fmt.Sprintf("🛑 STRICT BLOCK: %s spurious UP (%.0fms after previous UP)", buttonName, float64(upInterval.Nanoseconds())/1000000))
-			return 1
-		}
-		globalHook.lastUpTime[wParam] = now
-
-		// Only process if we have a corresponding button press
-		if globalHook.buttonPressed[downEvent] {
-			globalHook.lastCompleteClick = now
-			globalHook.lastClickButton = downEvent
-			globalHook.buttonPressed[downEvent] = false
-
-			// Analyze click pattern for faulty hardware detection
-			globalHook.detectFaultyHardware(downEvent, holdDuration)
-
-			// Log based on operation type
-			if globalHook.dragDetected[downEvent] {
-				globalHook.sendLog(fmt.Sprintf("✅ ALLOWED: %s button release after drag operation (%.0fms hold)",
-					buttonName, float64(holdDuration.Nanoseconds())/1000000))
-			} else if holdDuration > 200*time.Millisecond {
-				globalHook.sendLog(fmt.Sprintf("✅ ALLOWED: %s button release after long hold (%.0fms)",
-					buttonName, float64(holdDuration.Nanoseconds())/1000000))
-			} else {
-				globalHook.sendLog(fmt.Sprintf("✅ ALLOWED: %s button release - quick click (%.0fms)",
-					buttonName, float64(holdDuration.Nanoseconds())/1000000))
-			}
-
-			// Reset drag detection
-			globalHook.dragDetected[downEvent] = false
-		}
+	ev, ok := translateMessage(wParam, lParam)
+	if !ok {
+		return callNextHook(nCode, wParam, lParam)
+	}
 
-	case C.WPARAM(WM_MOUSEMOVE):
-		// Check if any button is currently pressed and mark as drag (but don't spam logs)
-		if globalHook.buttonPressed[C.WM_LBUTTONDOWN] && !globalHook.dragDetected[C.WM_LBUTTONDOWN] {
-			globalHook.dragDetected[C.WM_LBUTTONDOWN] = true
-			globalHook.sendLog("🖱️  Left button drag operation detected")
-		}
-		if globalHook.buttonPressed[C.WM_RBUTTONDOWN] && !globalHook.dragDetected[C.WM_RBUTTONDOWN] {
-			globalHook.dragDetected[C.WM_RBUTTONDOWN] = true
-			globalHook.sendLog("🖱️  Right button drag operation detected")
-		}
+	w.mu.Lock()
+	if w.bypass.pass(ev) {
+		w.observeBypassed(ev)
+		w.mu.Unlock()
+		return callNextHook(nCode, wParam, lParam)
+	}
+	d := w.engines.Process(ev)
+	w.report(Device{}, ev, d)
+	w.mu.Unlock()
+	if !d.Allow {
+		return 1 // Block the event
 	}
 
-	return C.CallNextHookEx(globalHook.hook, nCode, wParam, lParam)
+	return callNextHook(nCode, wParam, lParam)
 }
 
-func (w *windowsHook) Start(delay time.Duration, logChan chan string) error {
-	if w.isRunning {
+func (w *windowsHook) Start(cfg filter.Config, events chan<- Event) error {
+	if w.isRunning.Load() {
 		return fmt.Errorf("hook is already running")
 	}
 
-	w.delay = delay
-	w.logChannel = logChan
-	w.isRunning = true
-	w.buttonPressed = make(map[C.WPARAM]bool)
-	w.buttonPressTime = make(map[C.WPARAM]time.Time)
-	w.dragDetected = make(map[C.WPARAM]bool)
-	w.faultyClickPattern = make(map[C.WPARAM][]time.Time)
-	w.adaptiveDelay = make(map[C.WPARAM]time.Duration)
-	w.shortClickCount = make(map[C.WPARAM]int)
-	w.lastDownTime = make(map[C.WPARAM]time.Time)
-	w.lastDownBlocked = make(map[C.WPARAM]bool)
-	w.lastUpTime = make(map[C.WPARAM]time.Time)
-	globalHook = w
-
-	go func() {
-		w.hook = C.SetWindowsHookExW(C.WH_MOUSE_LL, C.HOOKPROC(C.LowLevelMouseProc), nil, 0)
-		if w.hook == nil {
-			w.logChannel <- "❌ Failed to install mouse hook"
-			w.isRunning = false
+	w.mu.Lock()
+	w.config = cfg
+	w.engines = filter.NewEngineSet(cfg)
+	w.events = events
+	w.mu.Unlock()
+	w.thread = newHookThread()
+	w.isRunning.Store(true)
+	globalHook.Store(w)
+
+	go w.thread.run(C.WH_MOUSE_LL, C.HOOKPROC(C.LowLevelMouseProc), func(ok bool) {
+		if !ok {
+			w.publish(Event{Type: EventError, Err: fmt.Errorf("failed to install mouse hook"), Fatal: true})
+			w.isRunning.Store(false)
+			globalHook.CompareAndSwap(w, nil)
 			return
 		}
-		w.logChannel <- "🎯 Mouse hook installed successfully - protection active!"
-		var msg C.MSG
-		for w.isRunning && C.GetMessage(&msg, nil, 0, 0) != 0 {
-			C.TranslateMessage(&msg)
-			C.DispatchMessage(&msg)
-		}
-	}()
+		w.publish(Event{Type: EventHookInstalled})
+	})
 
 	return nil
 }
 
 func (w *windowsHook) Stop() error {
-	if !w.isRunning {
+	if !w.isRunning.CompareAndSwap(true, false) {
 		return nil
 	}
 
-	w.isRunning = false
-	if w.hook != nil {
-		C.UnhookWindowsHookEx(w.hook)
-		w.hook = nil
-		if w.logChannel != nil {
-			w.logChannel <- "🛑 Mouse hook removed - protection stopped"
-		}
-	}
-	globalHook = nil
+	w.thread.stop()
+	globalHook.CompareAndSwap(w, nil)
+	w.publish(Event{Type: EventHookRemoved})
 	return nil
 }
 
 func (w *windowsHook) GetBlockedCount() int {
-	return w.blockedCount
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.stats.TotalBlockedClicks()
 }
 
+// ResetBlockedCount clears all statistics
 func (w *windowsHook) ResetBlockedCount() {
-	w.blockedCount = 0
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.stats = filter.NewStats()
 }
 
-func (w *windowsHook) IsSupported() bool {
-	return true
+func (w *windowsHook) Stats() filter.Stats {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.snapshot(w.engines)
 }
 
-// detectFaultyHardware analyzes click patterns to detect faulty mouse behavior
-func (w *windowsHook) detectFaultyHardware(button C.WPARAM, holdDuration time.Duration) {
-	// Track recent click times (keep last 10 clicks)
-	if w.faultyClickPattern[button] == nil {
-		w.faultyClickPattern[button] = make([]time.Time, 0, 10)
+// SetConfig switches the settings of a running hook
+func (w *windowsHook) SetConfig(cfg filter.Config) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.engines == nil {
+		return
 	}
+	w.config = cfg
+	w.engines.SetConfig(cfg)
+}
 
-	now := time.Now()
-	w.faultyClickPattern[button] = append(w.faultyClickPattern[button], now)
-	if len(w.faultyClickPattern[button]) > 10 {
-		w.faultyClickPattern[button] = w.faultyClickPattern[button][1:]
-	}
-
-	// Count very short clicks (likely insufficient pressure)
-	if holdDuration < 30*time.Millisecond {
-		w.shortClickCount[button]++
-		w.sendLog(fmt.Sprintf("📊 Short click detected (%.0fms) - total short clicks: %d",
-			float64(holdDuration.Nanoseconds())/1000000, w.shortClickCount[button]))
-	}
-
-	// Analyze pattern every 5 clicks
-	if len(w.faultyClickPattern[button]) >= 5 {
-		shortClicks := 0
-		for i := len(w.faultyClickPattern[button]) - 5; i < len(w.faultyClickPattern[button]); i++ {
-			// Check if this was a short click by looking at our count
-			if w.shortClickCount[button] > 0 {
-				shortClicks++
-			}
-		}
-
-		// Adaptive delay should never be less than user-selected delay
-		// Only increase delay for detected faulty patterns, never decrease
-		if float64(shortClicks)/5.0 > 0.6 {
-			// Increase delay for faulty hardware (never reduce below user setting)
-			newDelay := w.delay + (w.delay / 4) // Add 25% to user delay
-			if newDelay > 200*time.Millisecond {
-				newDelay = 200 * time.Millisecond // Maximum 200ms
-			}
-
-			buttonName := "Left"
-			if button == C.WM_RBUTTONDOWN {
-				buttonName = "Right"
-			}
+// SetObserver installs a callback that sees every processed event
+func (w *windowsHook) SetObserver(observer filter.Observer) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.observer = observer
+}
 
-			if w.adaptiveDelay[button] != newDelay {
-				w.adaptiveDelay[button] = newDelay
-				w.sendLog(fmt.Sprintf("🔧 ADAPTIVE STRICT: %s button delay increased to %.0fms due to detected low-pressure pattern",
-					buttonName, float64(newDelay.Nanoseconds())/1000000))
-			}
-		} else {
-			// Reset to user-selected delay if pattern improves
-			if w.adaptiveDelay[button] != w.delay {
-				w.adaptiveDelay[button] = w.delay
-				buttonName := "Left"
-				if button == C.WM_RBUTTONDOWN {
-					buttonName = "Right"
-				}
-				w.sendLog(fmt.Sprintf("🔧 ADAPTIVE STRICT: %s button delay reset to user setting (%.0fms)",
-					buttonName, float64(w.delay.Nanoseconds())/1000000))
-			}
-		}
-	}
+// Devices lists the attached mice. They are only listed: see
+// PerDeviceFiltering.
+func (w *windowsHook) Devices() ([]Device, error) {
+	return listRawMice(), nil
 }
 
-// getEffectiveDelay returns the adaptive delay for a specific button
-// Never returns a delay less than the user-selected delay
-func (w *windowsHook) getEffectiveDelay(button C.WPARAM) time.Duration {
-	if adaptiveDelay, exists := w.adaptiveDelay[button]; exists && adaptiveDelay >= w.delay {
-		return adaptiveDelay
-	}
-	return w.delay
+func (w *windowsHook) IsSupported() bool {
+	return true
 }
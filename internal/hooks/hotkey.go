@@ -0,0 +1,105 @@
+package hooks
+
+import (
+	"sync/atomic"
+
+	"click-guardian/internal/filter"
+	"click-guardian/internal/hotkey"
+)
+
+// HotkeyHook watches the keyboard for the global hotkeys. It only observes
+// keys, they still reach the focused application. A HotkeyHook runs while
+// protection is stopped too, so the hotkey can resume it.
+type HotkeyHook interface {
+	// Start publishes an EventHotkey whenever the toggle hotkey is pressed
+	// and lets the mouse hook bypass filtering while the modifier is held
+	Start(cfg hotkey.Config, events chan<- Event) error
+	Stop() error
+	IsSupported() bool
+}
+
+// NewHotkeyHook creates a new hotkey hook for the current platform
+func NewHotkeyHook() HotkeyHook {
+	return newPlatformHotkeyHook()
+}
+
+// bypassHeld is set while the bypass modifier of the running hotkey hook is
+// held; the mouse hooks read it for every click
+var bypassHeld atomic.Bool
+
+// hotkeyMatcher tracks the keys held down and recognizes the hotkeys
+type hotkeyMatcher struct {
+	config hotkey.Config
+	events chan<- Event
+	held   map[uint32]hotkey.Modifier // Keys held down by code, 0 for non-modifiers
+}
+
+func newHotkeyMatcher(cfg hotkey.Config, events chan<- Event) *hotkeyMatcher {
+	return &hotkeyMatcher{config: cfg, events: events, held: make(map[uint32]hotkey.Modifier)}
+}
+
+// modifiers returns the modifiers held down
+func (m *hotkeyMatcher) modifiers() hotkey.Modifier {
+	var mods hotkey.Modifier
+	for _, mod := range m.held {
+		mods |= mod
+	}
+	return mods
+}
+
+// key handles a key transition. mod is the modifier the key is, or 0.
+func (m *hotkeyMatcher) key(code uint32, name string, mod hotkey.Modifier, down bool) {
+	_, repeat := m.held[code]
+	if down {
+		m.held[code] = mod
+	} else {
+		delete(m.held, code)
+	}
+
+	if mod != 0 {
+		bypass := m.config.Bypass
+		bypassHeld.Store(bypass != 0 && m.modifiers()&bypass == bypass)
+		return
+	}
+	toggle := m.config.Toggle
+	if down && !repeat && toggle.Key != "" && name == toggle.Key && m.modifiers() == toggle.Modifiers {
+		send(m.events, Event{Type: EventHotkey, Key: toggle.String()})
+	}
+}
+
+// reset forgets the held keys and ends a bypass in progress
+func (m *hotkeyMatcher) reset() {
+	clear(m.held)
+	bypassHeld.Store(false)
+}
+
+// bypassGate lets clicks made while the bypass modifier is held through
+// unfiltered. The release of a bypassed press is let through as well, so
+// releasing the modifier first never leaves a button pressed.
+type bypassGate struct {
+	pressed map[filter.Button]bool
+}
+
+// pass reports whether ev bypasses the filter
+func (g *bypassGate) pass(ev filter.Event) bool {
+	switch ev.Kind {
+	case filter.KindDown:
+		if !bypassHeld.Load() {
+			return false
+		}
+		if g.pressed == nil {
+			g.pressed = make(map[filter.Button]bool)
+		}
+		g.pressed[ev.Button] = true
+		return true
+	case filter.KindUp:
+		if !g.pressed[ev.Button] {
+			return false
+		}
+		delete(g.pressed, ev.Button)
+		return true
+	case filter.KindWheel:
+		return bypassHeld.Load()
+	}
+	return false
+}
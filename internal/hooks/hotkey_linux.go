@@ -0,0 +1,131 @@
+//go:build linux
+
+package hooks
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+
+	"click-guardian/internal/hotkey"
+)
+
+// linuxHotkeyHook reads every keyboard through evdev without grabbing it
+type linuxHotkeyHook struct {
+	mu        sync.Mutex
+	matcher   *hotkeyMatcher
+	events    chan<- Event
+	devices   []*evdevDevice
+	wg        sync.WaitGroup
+	isRunning bool
+}
+
+// linuxModifiers maps the evdev codes of the modifier keys to modifiers
+var linuxModifiers = map[uint16]hotkey.Modifier{
+	29: hotkey.ModCtrl, 97: hotkey.ModCtrl, // KEY_LEFTCTRL, KEY_RIGHTCTRL
+	56: hotkey.ModAlt, 100: hotkey.ModAlt, // KEY_LEFTALT, KEY_RIGHTALT
+	42: hotkey.ModShift, 54: hotkey.ModShift, // KEY_LEFTSHIFT, KEY_RIGHTSHIFT
+	125: hotkey.ModSuper, 126: hotkey.ModSuper, // KEY_LEFTMETA, KEY_RIGHTMETA
+}
+
+// runningHotkeys is the running hotkey hook. Keyboards grabbed by the key
+// chatter filter no longer report to other readers, so that filter hands
+// the keys it lets through to this hook instead.
+var runningHotkeys atomic.Pointer[linuxHotkeyHook]
+
+func newPlatformHotkeyHook() HotkeyHook {
+	return &linuxHotkeyHook{}
+}
+
+func (h *linuxHotkeyHook) Start(cfg hotkey.Config, events chan<- Event) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.isRunning {
+		return fmt.Errorf("hotkey hook is already running")
+	}
+
+	devices, err := findDevices("keyboard", os.O_RDONLY, (*evdevDevice).isKeyboard)
+	if err != nil {
+		return permissionHint(err)
+	}
+
+	h.matcher = newHotkeyMatcher(cfg, events)
+	h.events = events
+	h.devices = devices
+	h.isRunning = true
+	runningHotkeys.Store(h)
+
+	for _, dev := range devices {
+		h.wg.Add(1)
+		go h.readLoop(dev)
+	}
+	return nil
+}
+
+// readLoop watches one keyboard until it is closed
+func (h *linuxHotkeyHook) readLoop(dev *evdevDevice) {
+	defer h.wg.Done()
+
+	buf := make([]byte, 64*inputEventSize)
+	for {
+		events, err := dev.readEvents(buf)
+		if err != nil {
+			if !errors.Is(err, os.ErrClosed) {
+				send(h.events, Event{Type: EventError, Device: dev.name, Err: err})
+			}
+			return
+		}
+		h.handle(events)
+	}
+}
+
+// handle passes the key events among events to the matcher
+func (h *linuxHotkeyHook) handle(events []inputEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !h.isRunning {
+		return
+	}
+	for _, ev := range events {
+		if ev.Type != evKey || ev.Code > keyMaxBasic {
+			continue
+		}
+		// Auto-repeats (value 2) keep the key held
+		h.matcher.key(uint32(ev.Code), hotkey.LinuxKeyName(ev.Code), linuxModifiers[ev.Code], ev.Value != 0)
+	}
+}
+
+// observeGrabbedKeys passes the events the key chatter filter re-emits to
+// the running hotkey hook
+func observeGrabbedKeys(events []inputEvent) {
+	if h := runningHotkeys.Load(); h != nil {
+		h.handle(events)
+	}
+}
+
+func (h *linuxHotkeyHook) Stop() error {
+	h.mu.Lock()
+	if !h.isRunning {
+		h.mu.Unlock()
+		return nil
+	}
+	h.isRunning = false
+	runningHotkeys.CompareAndSwap(h, nil)
+	for _, dev := range h.devices {
+		dev.close()
+	}
+	h.devices = nil
+	h.matcher.reset()
+	h.mu.Unlock()
+
+	h.wg.Wait()
+	return nil
+}
+
+func (h *linuxHotkeyHook) IsSupported() bool {
+	return true
+}
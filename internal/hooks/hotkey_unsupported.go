@@ -0,0 +1,27 @@
+//go:build !windows && !linux
+
+package hooks
+
+import (
+	"fmt"
+
+	"click-guardian/internal/hotkey"
+)
+
+type unsupportedHotkeyHook struct{}
+
+func newPlatformHotkeyHook() HotkeyHook {
+	return &unsupportedHotkeyHook{}
+}
+
+func (u *unsupportedHotkeyHook) Start(cfg hotkey.Config, events chan<- Event) error {
+	return fmt.Errorf("global hotkeys not supported on this platform")
+}
+
+func (u *unsupportedHotkeyHook) Stop() error {
+	return nil
+}
+
+func (u *unsupportedHotkeyHook) IsSupported() bool {
+	return false
+}
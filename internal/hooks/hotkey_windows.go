@@ -0,0 +1,103 @@
+//go:build windows
+
+package hooks
+
+/*
+#cgo LDFLAGS: -luser32 -lkernel32
+#include <windows.h>
+
+extern LRESULT LowLevelHotkeyProc(int nCode, WPARAM wParam, LPARAM lParam);
+*/
+import "C"
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+
+	"click-guardian/internal/hotkey"
+)
+
+type windowsHotkeyHook struct {
+	mu        sync.Mutex // Guards the matcher
+	matcher   *hotkeyMatcher
+	thread    *hookThread
+	isRunning atomic.Bool
+}
+
+func newPlatformHotkeyHook() HotkeyHook {
+	return &windowsHotkeyHook{}
+}
+
+var globalHotkeyHook atomic.Pointer[windowsHotkeyHook]
+
+// windowsModifiers maps the virtual-key codes of the modifier keys to
+// modifiers; the low-level hook reports the left and right keys apart
+var windowsModifiers = map[uint32]hotkey.Modifier{
+	0x11: hotkey.ModCtrl, 0xa2: hotkey.ModCtrl, 0xa3: hotkey.ModCtrl, // VK_CONTROL, VK_LCONTROL, VK_RCONTROL
+	0x12: hotkey.ModAlt, 0xa4: hotkey.ModAlt, 0xa5: hotkey.ModAlt, // VK_MENU, VK_LMENU, VK_RMENU
+	0x10: hotkey.ModShift, 0xa0: hotkey.ModShift, 0xa1: hotkey.ModShift, // VK_SHIFT, VK_LSHIFT, VK_RSHIFT
+	0x5b: hotkey.ModSuper, 0x5c: hotkey.ModSuper, // VK_LWIN, VK_RWIN
+}
+
+//export LowLevelHotkeyProc
+func LowLevelHotkeyProc(nCode C.int, wParam C.WPARAM, lParam C.LPARAM) C.LRESULT {
+	h := globalHotkeyHook.Load()
+	if nCode < 0 || h == nil {
+		return callNextHook(nCode, wParam, lParam)
+	}
+
+	info := (*C.KBDLLHOOKSTRUCT)(unsafe.Pointer(uintptr(lParam)))
+	vk := uint32(info.vkCode)
+	switch wParam {
+	case WM_KEYDOWN, WM_SYSKEYDOWN, WM_KEYUP, WM_SYSKEYUP:
+		down := wParam == WM_KEYDOWN || wParam == WM_SYSKEYDOWN
+		h.mu.Lock()
+		h.matcher.key(vk, hotkey.WindowsKeyName(vk), windowsModifiers[vk], down)
+		h.mu.Unlock()
+	}
+
+	// Hotkeys are only observed, the key still reaches the application
+	return callNextHook(nCode, wParam, lParam)
+}
+
+func (h *windowsHotkeyHook) Start(cfg hotkey.Config, events chan<- Event) error {
+	if h.isRunning.Load() {
+		return fmt.Errorf("hotkey hook is already running")
+	}
+
+	h.mu.Lock()
+	h.matcher = newHotkeyMatcher(cfg, events)
+	h.mu.Unlock()
+	h.thread = newHookThread()
+	h.isRunning.Store(true)
+	globalHotkeyHook.Store(h)
+
+	go h.thread.run(C.WH_KEYBOARD_LL, C.HOOKPROC(C.LowLevelHotkeyProc), func(ok bool) {
+		if !ok {
+			send(events, Event{Type: EventError, Err: fmt.Errorf("failed to install hotkey hook")})
+			h.isRunning.Store(false)
+			globalHotkeyHook.CompareAndSwap(h, nil)
+		}
+	})
+
+	return nil
+}
+
+func (h *windowsHotkeyHook) Stop() error {
+	if !h.isRunning.CompareAndSwap(true, false) {
+		return nil
+	}
+
+	h.thread.stop()
+	globalHotkeyHook.CompareAndSwap(h, nil)
+	h.mu.Lock()
+	h.matcher.reset()
+	h.mu.Unlock()
+	return nil
+}
+
+func (h *windowsHotkeyHook) IsSupported() bool {
+	return true
+}
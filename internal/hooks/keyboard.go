@@ -0,0 +1,44 @@
+package hooks
+
+import (
+	"click-guardian/internal/filter"
+)
+
+// KeyboardHook suppresses key chatter of physical keyboards
+type KeyboardHook interface {
+	Start(cfg filter.KeyConfig, events chan<- Event) error
+	Stop() error
+	Stats() filter.KeyStats
+	ResetStats()
+	IsSupported() bool
+}
+
+// NewKeyboardHook creates a new keyboard hook for the current platform
+func NewKeyboardHook() KeyboardHook {
+	return newPlatformKeyboardHook()
+}
+
+// keyReporter keeps the keyboard statistics and publishes blocked presses.
+// Allowed keys are never published, so typed text cannot be read from the
+// events, the log or the control socket.
+type keyReporter struct {
+	events chan<- Event
+	stats  filter.KeyStats
+}
+
+// report updates the statistics and publishes a blocked press
+func (r *keyReporter) report(dev string, ev filter.KeyEvent, d filter.KeyDecision) {
+	r.stats.Record(ev, d)
+	if d.Allow || !ev.Down || d.Repeat {
+		return
+	}
+	send(r.events, Event{
+		Type:     EventKey,
+		Time:     ev.Time,
+		Key:      ev.Name,
+		Kind:     filter.KindDown,
+		Interval: d.Interval,
+		Delay:    d.Threshold,
+		Device:   dev,
+	})
+}
@@ -0,0 +1,223 @@
+//go:build linux
+
+package hooks
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"click-guardian/internal/filter"
+	"click-guardian/internal/hotkey"
+)
+
+// linuxKeyboardHook grabs every keyboard through evdev, drops key chatter
+// and re-emits the remaining events through a uinput virtual keyboard
+type linuxKeyboardHook struct {
+	keyReporter
+	mu        sync.Mutex
+	engine    *filter.KeyEngine
+	devices   []*evdevDevice
+	output    *uinputDevice
+	wg        sync.WaitGroup
+	isRunning bool
+}
+
+// How long Start waits for held keys to be released before grabbing
+const (
+	releaseWaitStep  = 20 * time.Millisecond
+	releaseWaitSteps = 100
+)
+
+func newPlatformKeyboardHook() KeyboardHook {
+	return &linuxKeyboardHook{}
+}
+
+func (k *linuxKeyboardHook) Start(cfg filter.KeyConfig, events chan<- Event) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.isRunning {
+		return fmt.Errorf("keyboard hook is already running")
+	}
+
+	output, err := createUinputKeyboard()
+	if err != nil {
+		return permissionHint(fmt.Errorf("failed to open /dev/uinput: %w", err))
+	}
+
+	devices, err := findKeyboards()
+	if err != nil {
+		output.close()
+		return permissionHint(err)
+	}
+
+	for i, dev := range devices {
+		// A key released after the grab would stay pressed for applications,
+		// such as the Enter that started us from a terminal
+		for wait := 0; dev.keysDown() && wait < releaseWaitSteps; wait++ {
+			time.Sleep(releaseWaitStep)
+		}
+		if err := dev.grab(); err != nil {
+			for _, d := range devices {
+				d.close()
+			}
+			output.close()
+			return fmt.Errorf("failed to grab %s (%s): %v", devices[i].name, devices[i].path, err)
+		}
+	}
+
+	k.events = events
+	k.engine = filter.NewKeyEngine(cfg)
+	k.output = output
+	k.devices = devices
+	k.isRunning = true
+
+	for _, dev := range devices {
+		send(events, Event{Type: EventDeviceGrabbed, Device: fmt.Sprintf("%s (%s)", dev.device(), dev.path), DeviceID: dev.device().ID})
+		k.wg.Add(1)
+		go k.readLoop(dev, output)
+	}
+	k.wg.Add(1)
+	go k.forwardLEDs(output, devices)
+
+	return nil
+}
+
+// readLoop filters one keyboard into output until the keyboard is closed
+func (k *linuxKeyboardHook) readLoop(dev *evdevDevice, output *uinputDevice) {
+	defer k.wg.Done()
+
+	buf := make([]byte, 64*inputEventSize)
+	var frame []inputEvent
+	for {
+		events, err := dev.readEvents(buf)
+		if err != nil {
+			if !errors.Is(err, os.ErrClosed) {
+				send(k.events, Event{Type: EventError, Device: dev.name, Err: err})
+			}
+			return
+		}
+		for _, ev := range events {
+			if ev.Type != evSyn {
+				frame = append(frame, ev)
+				continue
+			}
+			if ev.Code == synReport {
+				out := k.filterFrame(dev.name, frame)
+				k.emit(output, out)
+				observeGrabbedKeys(out)
+			}
+			// SYN_DROPPED and other sync events discard the partial frame
+			frame = frame[:0]
+		}
+	}
+}
+
+// filterFrame runs the key events of one report through the engine and
+// returns the events that should be re-emitted
+func (k *linuxKeyboardHook) filterFrame(dev string, frame []inputEvent) []inputEvent {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if !k.isRunning {
+		return nil
+	}
+
+	out := make([]inputEvent, 0, len(frame))
+	for _, ev := range frame {
+		// Buttons some keyboards report share EV_KEY, they are not filtered
+		if ev.Type != evKey || (ev.Code > keyMaxBasic && ev.Code < keyOk) {
+			out = append(out, ev)
+			continue
+		}
+
+		kev := filter.KeyEvent{
+			Code: uint32(ev.Code),
+			Name: hotkey.LinuxKeyName(ev.Code),
+			Down: ev.Value != 0, // 1 is a press, 2 an auto-repeat
+			Time: eventTime(ev),
+		}
+		d := k.engine.Process(kev)
+		k.report(dev, kev, d)
+		if d.Allow {
+			out = append(out, ev)
+		}
+	}
+	return out
+}
+
+func (k *linuxKeyboardHook) emit(output *uinputDevice, events []inputEvent) {
+	if err := output.write(events); err != nil && !errors.Is(err, os.ErrClosed) {
+		send(k.events, Event{Type: EventError, Device: virtualKeyboardName, Err: err})
+	}
+}
+
+// forwardLEDs passes Caps Lock and the other LEDs applications set on the
+// virtual keyboard output on to the grabbed keyboards
+func (k *linuxKeyboardHook) forwardLEDs(output *uinputDevice, devices []*evdevDevice) {
+	defer k.wg.Done()
+
+	buf := make([]byte, 16*inputEventSize)
+	for {
+		events, err := output.read(buf)
+		if err != nil {
+			return
+		}
+		var leds []inputEvent
+		for _, ev := range events {
+			if ev.Type == evLed {
+				leds = append(leds, ev)
+			}
+		}
+		if len(leds) == 0 {
+			continue
+		}
+		leds = append(leds, inputEvent{Type: evSyn, Code: synReport})
+		for _, dev := range devices {
+			// Keyboards without LEDs reject the write, which is harmless
+			dev.write(leds)
+		}
+	}
+}
+
+func (k *linuxKeyboardHook) Stop() error {
+	k.mu.Lock()
+	if !k.isRunning {
+		k.mu.Unlock()
+		return nil
+	}
+	k.isRunning = false
+	for _, dev := range k.devices {
+		dev.close()
+	}
+	k.devices = nil
+	output := k.output
+	k.output = nil
+	k.mu.Unlock()
+
+	// Closing the virtual keyboard ends the LED forwarding; readers still
+	// emitting a frame get os.ErrClosed, which is not reported
+	output.close()
+	k.wg.Wait()
+	return nil
+}
+
+func (k *linuxKeyboardHook) Stats() filter.KeyStats {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return k.stats.Clone()
+}
+
+// ResetStats clears the keyboard statistics
+func (k *linuxKeyboardHook) ResetStats() {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.stats = filter.NewKeyStats()
+}
+
+func (k *linuxKeyboardHook) IsSupported() bool {
+	return true
+}
@@ -0,0 +1,35 @@
+//go:build !windows && !linux
+
+package hooks
+
+import (
+	"fmt"
+
+	"click-guardian/internal/filter"
+)
+
+type unsupportedKeyboardHook struct{}
+
+func newPlatformKeyboardHook() KeyboardHook {
+	return &unsupportedKeyboardHook{}
+}
+
+func (u *unsupportedKeyboardHook) Start(cfg filter.KeyConfig, events chan<- Event) error {
+	return fmt.Errorf("keyboard hooking not supported on this platform")
+}
+
+func (u *unsupportedKeyboardHook) Stop() error {
+	return nil
+}
+
+func (u *unsupportedKeyboardHook) Stats() filter.KeyStats {
+	return filter.NewKeyStats()
+}
+
+func (u *unsupportedKeyboardHook) ResetStats() {
+	// No-op
+}
+
+func (u *unsupportedKeyboardHook) IsSupported() bool {
+	return false
+}
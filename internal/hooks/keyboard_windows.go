@@ -0,0 +1,134 @@
+//go:build windows
+
+package hooks
+
+/*
+#cgo LDFLAGS: -luser32 -lkernel32
+#include <windows.h>
+
+extern LRESULT LowLevelKeyboardProc(int nCode, WPARAM wParam, LPARAM lParam);
+*/
+import "C"
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unsafe"
+
+	"click-guardian/internal/filter"
+	"click-guardian/internal/hotkey"
+)
+
+// Keyboard message constants
+const (
+	WM_KEYDOWN    = 0x0100
+	WM_KEYUP      = 0x0101
+	WM_SYSKEYDOWN = 0x0104
+	WM_SYSKEYUP   = 0x0105
+
+	LLKHF_INJECTED = 0x10
+)
+
+type windowsKeyboardHook struct {
+	keyReporter
+	mu        sync.Mutex // Guards the engine and statistics
+	engine    *filter.KeyEngine
+	thread    *hookThread
+	isRunning atomic.Bool
+}
+
+func newPlatformKeyboardHook() KeyboardHook {
+	return &windowsKeyboardHook{}
+}
+
+var globalKeyboardHook atomic.Pointer[windowsKeyboardHook]
+
+//export LowLevelKeyboardProc
+func LowLevelKeyboardProc(nCode C.int, wParam C.WPARAM, lParam C.LPARAM) C.LRESULT {
+	k := globalKeyboardHook.Load()
+	if nCode < 0 || k == nil {
+		return callNextHook(nCode, wParam, lParam)
+	}
+
+	info := (*C.KBDLLHOOKSTRUCT)(unsafe.Pointer(uintptr(lParam)))
+	// Input synthesized by software never chatters
+	if uint32(info.flags)&LLKHF_INJECTED != 0 {
+		return callNextHook(nCode, wParam, lParam)
+	}
+
+	ev := filter.KeyEvent{
+		Code: uint32(info.vkCode),
+		Name: hotkey.WindowsKeyName(uint32(info.vkCode)),
+		Time: time.Now(),
+	}
+	switch wParam {
+	case WM_KEYDOWN, WM_SYSKEYDOWN:
+		ev.Down = true
+	case WM_KEYUP, WM_SYSKEYUP:
+	default:
+		return callNextHook(nCode, wParam, lParam)
+	}
+
+	k.mu.Lock()
+	d := k.engine.Process(ev)
+	k.report("", ev, d)
+	k.mu.Unlock()
+	if !d.Allow {
+		return 1 // Block the event
+	}
+
+	return callNextHook(nCode, wParam, lParam)
+}
+
+func (k *windowsKeyboardHook) Start(cfg filter.KeyConfig, events chan<- Event) error {
+	if k.isRunning.Load() {
+		return fmt.Errorf("keyboard hook is already running")
+	}
+
+	k.mu.Lock()
+	k.engine = filter.NewKeyEngine(cfg)
+	k.events = events
+	k.mu.Unlock()
+	k.thread = newHookThread()
+	k.isRunning.Store(true)
+	globalKeyboardHook.Store(k)
+
+	go k.thread.run(C.WH_KEYBOARD_LL, C.HOOKPROC(C.LowLevelKeyboardProc), func(ok bool) {
+		if !ok {
+			send(events, Event{Type: EventError, Err: fmt.Errorf("failed to install keyboard hook")})
+			k.isRunning.Store(false)
+			globalKeyboardHook.CompareAndSwap(k, nil)
+		}
+	})
+
+	return nil
+}
+
+func (k *windowsKeyboardHook) Stop() error {
+	if !k.isRunning.CompareAndSwap(true, false) {
+		return nil
+	}
+
+	k.thread.stop()
+	globalKeyboardHook.CompareAndSwap(k, nil)
+	return nil
+}
+
+func (k *windowsKeyboardHook) Stats() filter.KeyStats {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return k.stats.Clone()
+}
+
+// ResetStats clears the keyboard statistics
+func (k *windowsKeyboardHook) ResetStats() {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.stats = filter.NewKeyStats()
+}
+
+func (k *windowsKeyboardHook) IsSupported() bool {
+	return true
+}
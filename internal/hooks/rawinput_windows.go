@@ -0,0 +1,120 @@
+//go:build windows
+
+package hooks
+
+/*
+#cgo LDFLAGS: -luser32 -lkernel32 -lhid
+#include <windows.h>
+#include <stdlib.h>
+
+BOOLEAN __stdcall HidD_GetProductString(HANDLE device, PVOID buffer, ULONG length);
+
+// listMice stores up to max raw input mice in handles and returns how many
+static UINT listMice(HANDLE *handles, UINT max) {
+	UINT n = 0;
+	if (GetRawInputDeviceList(NULL, &n, sizeof(RAWINPUTDEVICELIST)) != 0 || n == 0) {
+		return 0;
+	}
+	RAWINPUTDEVICELIST *list = malloc(n * sizeof(RAWINPUTDEVICELIST));
+	if (list == NULL) {
+		return 0;
+	}
+	n = GetRawInputDeviceList(list, &n, sizeof(RAWINPUTDEVICELIST));
+	UINT count = 0;
+	for (UINT i = 0; n != (UINT)-1 && i < n && count < max; i++) {
+		if (list[i].dwType == RIM_TYPEMOUSE) {
+			handles[count++] = list[i].hDevice;
+		}
+	}
+	free(list);
+	return count;
+}
+
+// devicePath copies the interface path of a raw input device and returns
+// its length, 0 on failure
+static UINT devicePath(HANDLE device, WCHAR *path, UINT size) {
+	UINT n = size;
+	UINT r = GetRawInputDeviceInfoW(device, RIDI_DEVICENAME, path, &n);
+	return r == (UINT)-1 ? 0 : r;
+}
+
+// productName reads the product string of a HID device
+static int productName(const WCHAR *path, WCHAR *name, ULONG size) {
+	HANDLE h = CreateFileW(path, 0, FILE_SHARE_READ | FILE_SHARE_WRITE, NULL, OPEN_EXISTING, 0, NULL);
+	if (h == INVALID_HANDLE_VALUE) {
+		return 0;
+	}
+	BOOLEAN ok = HidD_GetProductString(h, name, size);
+	CloseHandle(h);
+	return ok;
+}
+*/
+import "C"
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// describeRawDevice reads the vendor and product ID and the name of a raw
+// input device
+func describeRawDevice(handle C.HANDLE) Device {
+	key := fmt.Sprintf("%#x", uintptr(unsafe.Pointer(handle)))
+	buf := make([]uint16, 512)
+	n := C.devicePath(handle, (*C.WCHAR)(unsafe.Pointer(&buf[0])), C.UINT(len(buf)))
+	if n == 0 {
+		return Device{ID: DeviceID(0, 0), Key: key, Name: "Unknown mouse"}
+	}
+	path := windows.UTF16ToString(buf[:n])
+
+	// Interface paths look like \\?\HID#VID_046D&PID_C077&MI_00#...
+	vendor := hexAfter(path, "VID_")
+	product := hexAfter(path, "PID_")
+	dev := Device{
+		ID:      DeviceID(vendor, product),
+		Key:     key,
+		Vendor:  vendor,
+		Product: product,
+	}
+
+	name := make([]uint16, 127) // HID strings are limited to 126 characters
+	if C.productName((*C.WCHAR)(unsafe.Pointer(&buf[0])), (*C.WCHAR)(unsafe.Pointer(&name[0])), C.ULONG(2*len(name))) != 0 {
+		dev.Name = strings.TrimSpace(windows.UTF16ToString(name))
+	}
+	if dev.Name == "" {
+		if parts := strings.Split(path, "#"); len(parts) > 1 {
+			dev.Name = parts[1]
+		} else {
+			dev.Name = path
+		}
+	}
+	return dev
+}
+
+// hexAfter parses the four hex digits following prefix in s, ignoring case
+func hexAfter(s, prefix string) uint16 {
+	i := strings.Index(strings.ToUpper(s), prefix)
+	if i < 0 || i+len(prefix)+4 > len(s) {
+		return 0
+	}
+	v, err := strconv.ParseUint(s[i+len(prefix):i+len(prefix)+4], 16, 16)
+	if err != nil {
+		return 0
+	}
+	return uint16(v)
+}
+
+// listRawMice describes every attached raw input mouse
+func listRawMice() []Device {
+	handles := make([]C.HANDLE, 64)
+	n := C.listMice(&handles[0], C.UINT(len(handles)))
+	devices := make([]Device, 0, n)
+	for _, h := range handles[:n] {
+		devices = append(devices, describeRawDevice(h))
+	}
+	return devices
+}
@@ -0,0 +1,79 @@
+//go:build windows
+
+package hooks
+
+/*
+#cgo LDFLAGS: -luser32
+#include <windows.h>
+*/
+import "C"
+
+import (
+	"runtime"
+	"sync"
+)
+
+// hookThread runs a low-level hook on a thread of its own. The hook is
+// installed and removed on that thread, which pumps messages until stop is
+// called; nothing else touches the hook handle.
+type hookThread struct {
+	mu      sync.Mutex // Guards thread and stopped
+	thread  C.DWORD    // Thread running the message loop, 0 until it runs
+	stopped bool
+	done    chan struct{}
+}
+
+func newHookThread() *hookThread {
+	return &hookThread{done: make(chan struct{})}
+}
+
+// run installs the hook and pumps messages until stop is called. It tells
+// installed whether the hook could be installed before it handles any
+// message.
+func (t *hookThread) run(idHook C.int, proc C.HOOKPROC, installed func(ok bool)) {
+	defer close(t.done)
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	var msg C.MSG
+	// Creates the message queue of the thread, so stop can post to it
+	C.PeekMessageW(&msg, nil, 0, 0, C.PM_NOREMOVE)
+
+	hook := C.SetWindowsHookExW(idHook, proc, nil, 0)
+	installed(hook != nil)
+	if hook == nil {
+		return
+	}
+	defer C.UnhookWindowsHookEx(hook)
+
+	t.mu.Lock()
+	if t.stopped {
+		t.mu.Unlock()
+		return
+	}
+	t.thread = C.GetCurrentThreadId()
+	t.mu.Unlock()
+
+	for C.GetMessage(&msg, nil, 0, 0) > 0 {
+		C.TranslateMessage(&msg)
+		C.DispatchMessage(&msg)
+	}
+}
+
+// stop ends the message loop and waits until the hook is removed
+func (t *hookThread) stop() {
+	t.mu.Lock()
+	t.stopped = true
+	if t.thread != 0 {
+		// The loop has no window to wake it up
+		C.PostThreadMessageW(t.thread, C.WM_QUIT, 0, 0)
+	}
+	t.mu.Unlock()
+	<-t.done
+}
+
+// callNextHook passes an event on to the next hook. CallNextHookEx ignores
+// its hook handle, so none is needed.
+func callNextHook(nCode C.int, wParam C.WPARAM, lParam C.LPARAM) C.LRESULT {
+	return C.CallNextHookEx(nil, nCode, wParam, lParam)
+}
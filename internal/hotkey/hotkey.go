@@ -0,0 +1,126 @@
+// Package hotkey parses the global hotkey and bypass modifier settings.
+// It has no dependencies so the configuration can use it without pulling in
+// the platform hooks that watch for the keys.
+package hotkey
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Modifier is a set of modifier keys
+type Modifier uint8
+
+const (
+	ModCtrl Modifier = 1 << iota
+	ModAlt
+	ModShift
+	ModSuper
+)
+
+// modifierNames lists the modifiers in the order they are written
+var modifierNames = []struct {
+	mod  Modifier
+	name string
+}{
+	{ModCtrl, "Ctrl"},
+	{ModAlt, "Alt"},
+	{ModShift, "Shift"},
+	{ModSuper, "Super"},
+}
+
+// parseModifierName returns the modifier a name refers to, or 0
+func parseModifierName(name string) Modifier {
+	switch strings.ToLower(name) {
+	case "ctrl", "control":
+		return ModCtrl
+	case "alt":
+		return ModAlt
+	case "shift":
+		return ModShift
+	case "super", "win", "meta", "cmd":
+		return ModSuper
+	}
+	return 0
+}
+
+// String returns the modifiers as written in the settings, e.g. "Ctrl+Alt"
+func (m Modifier) String() string {
+	var names []string
+	for _, n := range modifierNames {
+		if m&n.mod != 0 {
+			names = append(names, n.name)
+		}
+	}
+	return strings.Join(names, "+")
+}
+
+// ParseModifier parses modifiers such as "Ctrl" or "Ctrl+Shift". An empty
+// string is no modifier.
+func ParseModifier(s string) (Modifier, error) {
+	var m Modifier
+	if strings.TrimSpace(s) == "" {
+		return 0, nil
+	}
+	for _, part := range strings.Split(s, "+") {
+		mod := parseModifierName(strings.TrimSpace(part))
+		if mod == 0 {
+			return 0, fmt.Errorf("unknown modifier %q, use Ctrl, Alt, Shift or Super", strings.TrimSpace(part))
+		}
+		m |= mod
+	}
+	return m, nil
+}
+
+// Hotkey is a key pressed together with modifiers
+type Hotkey struct {
+	Modifiers Modifier
+	Key       string // Key name as used for key chatter thresholds, e.g. "P" or "F9"
+}
+
+// Parse parses a hotkey such as "Ctrl+Alt+P". An empty string is no hotkey.
+// The key has to be one the keyboard hooks report.
+func Parse(s string) (Hotkey, error) {
+	var h Hotkey
+	if strings.TrimSpace(s) == "" {
+		return h, nil
+	}
+	for _, part := range strings.Split(s, "+") {
+		part = strings.TrimSpace(part)
+		if mod := parseModifierName(part); mod != 0 {
+			h.Modifiers |= mod
+			continue
+		}
+		if part == "" || h.Key != "" {
+			return Hotkey{}, fmt.Errorf("hotkey %q must name exactly one key besides its modifiers", s)
+		}
+		h.Key = strings.ToUpper(part)
+		if !validKey(h.Key) {
+			return Hotkey{}, fmt.Errorf("unknown key %q in hotkey %q, use a letter, a digit, F1 to F12 or one of %s",
+				part, s, strings.Join(specialKeys(), ", "))
+		}
+	}
+	if h.Key == "" {
+		return Hotkey{}, fmt.Errorf("hotkey %q has no key besides its modifiers", s)
+	}
+	return h, nil
+}
+
+// String returns the hotkey as written in the settings
+func (h Hotkey) String() string {
+	if h.Modifiers == 0 {
+		return h.Key
+	}
+	return h.Modifiers.String() + "+" + h.Key
+}
+
+// Config holds the global hotkey settings
+type Config struct {
+	Toggle Hotkey   // Pauses or resumes protection, none when Key is empty
+	Bypass Modifier // Lets clicks through unfiltered while held, none when 0
+}
+
+// Enabled reports whether the configuration needs a hotkey hook
+func (c Config) Enabled() bool {
+	return c.Toggle.Key != "" || c.Bypass != 0
+}
@@ -0,0 +1,105 @@
+package hotkey
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Hotkey
+		text string // String of the result, "" when in is invalid
+	}{
+		{"", Hotkey{}, ""},
+		{"Ctrl+Alt+P", Hotkey{Modifiers: ModCtrl | ModAlt, Key: "P"}, "Ctrl+Alt+P"},
+		{" alt + control + f9 ", Hotkey{Modifiers: ModCtrl | ModAlt, Key: "F9"}, "Ctrl+Alt+F9"},
+		{"Win+Shift+pause", Hotkey{Modifiers: ModShift | ModSuper, Key: "PAUSE"}, "Shift+Super+PAUSE"},
+		{"F12", Hotkey{Key: "F12"}, "F12"},
+		{"Ctrl+Alt", Hotkey{}, ""},
+		{"Ctrl+P+Q", Hotkey{}, ""},
+		{"Ctrl++P", Hotkey{}, ""},
+		{"Ctrl+space", Hotkey{Modifiers: ModCtrl, Key: "SPACE"}, "Ctrl+SPACE"},
+		{"Alt+key_99", Hotkey{Modifiers: ModAlt, Key: "KEY_99"}, "Alt+KEY_99"},
+		// Keys the hooks never report could not be pressed
+		{"Ctrl+Alt+Pasue", Hotkey{}, ""},
+		{"Ctrl+F13", Hotkey{}, ""},
+		{"Ctrl+VK_X", Hotkey{}, ""},
+	}
+
+	for _, tt := range tests {
+		got, err := Parse(tt.in)
+		valid := tt.text != "" || tt.in == ""
+		if (err == nil) != valid {
+			t.Errorf("Parse(%q) error = %v, want valid %v", tt.in, err, valid)
+			continue
+		}
+		if got != tt.want || got.String() != tt.text {
+			t.Errorf("Parse(%q) = %+v (%q), want %+v (%q)", tt.in, got, got.String(), tt.want, tt.text)
+		}
+	}
+}
+
+func TestParseModifier(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Modifier
+		ok   bool
+	}{
+		{"", 0, true},
+		{"Ctrl", ModCtrl, true},
+		{"shift+META", ModShift | ModSuper, true},
+		{"Hyper", 0, false},
+		{"Ctrl+P", 0, false},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseModifier(tt.in)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("ParseModifier(%q) = %v, %v; want %v, ok %v", tt.in, got, err, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestKeyNames(t *testing.T) {
+	tests := []struct {
+		linux   uint16
+		windows uint32
+		want    string
+	}{
+		{0x1e, 'A', "A"},
+		{0x0b, '0', "0"},
+		{0x3b, 0x70, "F1"},
+		{0x58, 0x7b, "F12"},
+		{0x77, 0x13, "PAUSE"},
+		{0x34, 0xbe, "DOT"},
+	}
+
+	for _, tt := range tests {
+		if got := LinuxKeyName(tt.linux); got != tt.want {
+			t.Errorf("LinuxKeyName(%#x) = %q, want %q", tt.linux, got, tt.want)
+		}
+		if got := WindowsKeyName(tt.windows); got != tt.want {
+			t.Errorf("WindowsKeyName(%#x) = %q, want %q", tt.windows, got, tt.want)
+		}
+	}
+
+	// Both platforms name the same keys, so a hotkey works on either
+	linux := make(map[string]bool)
+	for _, name := range linuxKeyNames {
+		linux[name] = true
+	}
+	for _, name := range windowsKeyNames {
+		if !linux[name] {
+			t.Errorf("%s is only named on Windows", name)
+		}
+		delete(linux, name)
+	}
+	for name := range linux {
+		t.Errorf("%s is only named on Linux", name)
+	}
+
+	if got := LinuxKeyName(99); got != "KEY_99" || !validKey(got) {
+		t.Errorf("unnamed evdev key is %q, want a valid KEY_99", got)
+	}
+	if got := WindowsKeyName(0x2c); got != "VK_44" || !validKey(got) {
+		t.Errorf("unnamed virtual key is %q, want a valid VK_44", got)
+	}
+}
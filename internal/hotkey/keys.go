@@ -0,0 +1,110 @@
+package hotkey
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// linuxKeyNames maps evdev key codes to the names used in the settings
+var linuxKeyNames = func() map[uint16]string {
+	names := map[uint16]string{
+		0x01: "ESC", 0x0c: "MINUS", 0x0d: "EQUAL", 0x0e: "BACKSPACE", 0x0f: "TAB",
+		0x1c: "ENTER", 0x27: "SEMICOLON", 0x33: "COMMA", 0x34: "DOT", 0x35: "SLASH",
+		0x39: "SPACE", 0x57: "F11", 0x58: "F12", 0x77: "PAUSE",
+	}
+	rows := []struct {
+		first uint16
+		keys  string
+	}{
+		{0x02, "1234567890"},
+		{0x10, "QWERTYUIOP"},
+		{0x1e, "ASDFGHJKL"},
+		{0x2c, "ZXCVBNM"},
+	}
+	for _, row := range rows {
+		for i, key := range row.keys {
+			names[row.first+uint16(i)] = string(key)
+		}
+	}
+	for i := uint16(0); i < 10; i++ {
+		names[0x3b+i] = fmt.Sprintf("F%d", i+1)
+	}
+	return names
+}()
+
+// windowsKeyNames maps virtual-key codes to the names used in the settings
+var windowsKeyNames = func() map[uint32]string {
+	names := map[uint32]string{
+		0x08: "BACKSPACE", 0x09: "TAB", 0x0d: "ENTER", 0x13: "PAUSE", 0x1b: "ESC", 0x20: "SPACE",
+		0xba: "SEMICOLON", 0xbb: "EQUAL", 0xbc: "COMMA", 0xbd: "MINUS", 0xbe: "DOT", 0xbf: "SLASH",
+	}
+	for c := uint32('0'); c <= '9'; c++ {
+		names[c] = string(rune(c))
+	}
+	for c := uint32('A'); c <= 'Z'; c++ {
+		names[c] = string(rune(c))
+	}
+	for i := uint32(0); i < 12; i++ {
+		names[0x70+i] = fmt.Sprintf("F%d", i+1)
+	}
+	return names
+}()
+
+// keyNames holds every named key of both platforms
+var keyNames = func() map[string]bool {
+	names := make(map[string]bool)
+	for _, name := range linuxKeyNames {
+		names[name] = true
+	}
+	for _, name := range windowsKeyNames {
+		names[name] = true
+	}
+	return names
+}()
+
+// LinuxKeyName returns the name of an evdev key code
+func LinuxKeyName(code uint16) string {
+	if name, ok := linuxKeyNames[code]; ok {
+		return name
+	}
+	return fmt.Sprintf("KEY_%d", code)
+}
+
+// WindowsKeyName returns the name of a virtual-key code
+func WindowsKeyName(vk uint32) string {
+	if name, ok := windowsKeyNames[vk]; ok {
+		return name
+	}
+	return fmt.Sprintf("VK_%d", vk)
+}
+
+// validKey reports whether the hooks report a key by name, which is upper
+// case. Keys without a name are accepted by their platform code, e.g.
+// "KEY_99" or "VK_44".
+func validKey(name string) bool {
+	if keyNames[name] {
+		return true
+	}
+	for _, prefix := range []string{"KEY_", "VK_"} {
+		if code, ok := strings.CutPrefix(name, prefix); ok {
+			_, err := strconv.ParseUint(code, 10, 16)
+			return err == nil
+		}
+	}
+	return false
+}
+
+// specialKeys returns the named keys other than letters, digits and
+// function keys, sorted, for error messages
+func specialKeys() []string {
+	var names []string
+	for name := range keyNames {
+		if len(name) > 1 && !(name[0] == 'F' && name[1] >= '0' && name[1] <= '9') {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
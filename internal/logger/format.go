@@ -0,0 +1,96 @@
+package logger
+
+import (
+	"fmt"
+	"time"
+
+	"click-guardian/internal/filter"
+	"click-guardian/internal/hooks"
+)
+
+// FormatEvent renders a hook event as a log line. Events that are not worth
+// logging return an empty string.
+func FormatEvent(ev hooks.Event) string {
+	button := ev.Button.String()
+	interval := ms(ev.Interval)
+	delay := ms(ev.Delay)
+
+	switch ev.Type {
+	case hooks.EventButton:
+		if !ev.Allowed {
+			switch ev.Reason {
+			case filter.ReasonRapidDown:
+				return fmt.Sprintf("🛑 STRICT BLOCK: %s hardware bounce/double-click (%.0fms after previous DOWN, delay: %.0fms)", button, interval, delay)
+			case filter.ReasonBounce:
+				return fmt.Sprintf("🚫 STRICT BLOCK: %s button hardware bounce (%.0fms since press, delay: %.0fms)", button, interval, delay)
+			case filter.ReasonRapidClick:
+				return fmt.Sprintf("🚫 STRICT BLOCK: %s button rapid double-click (%.0fms after complete click, delay: %.0fms)", button, interval, delay)
+			case filter.ReasonSpuriousUp:
+				return fmt.Sprintf("🛑 STRICT BLOCK: %s spurious UP without a press (%.0fms after previous UP)", button, interval)
+			default:
+				return fmt.Sprintf("🛑 STRICT BLOCK: %s button (%s)", button, ev.Reason)
+			}
+		}
+
+		if ev.Kind == filter.KindDown {
+			if ev.DoubleClick {
+				return fmt.Sprintf("✅ ALLOWED: %s button double-click (%.0fms after release, delay: %.0fms)", button, interval, delay)
+			}
+			return fmt.Sprintf("✅ ALLOWED: %s button press", button)
+		}
+
+		short := ""
+		if ev.ShortClicks > 0 {
+			short = fmt.Sprintf(" 📊 short click #%d", ev.ShortClicks)
+		}
+		if ev.Drag {
+			return fmt.Sprintf("✅ ALLOWED: %s button release after drag operation (%.0fms hold)%s", button, ms(ev.Hold), short)
+		} else if ev.Hold > 200*time.Millisecond {
+			return fmt.Sprintf("✅ ALLOWED: %s button release after long hold (%.0fms)%s", button, ms(ev.Hold), short)
+		}
+		return fmt.Sprintf("✅ ALLOWED: %s button release - quick click (%.0fms)%s", button, ms(ev.Hold), short)
+
+	case hooks.EventWheel:
+		if ev.Allowed {
+			return ""
+		}
+		return fmt.Sprintf("🌀 WHEEL BLOCK: isolated direction reversal (%.0fms after previous tick, window: %.0fms)", interval, delay)
+
+	case hooks.EventKey:
+		return fmt.Sprintf("⌨️ KEY BLOCK: %s key chatter (%.0fms after its release, threshold: %.0fms)", ev.Key, interval, delay)
+
+	case hooks.EventHotkey:
+		return fmt.Sprintf("⌨️ Hotkey %s pressed", ev.Key)
+
+	case hooks.EventDrag:
+		return fmt.Sprintf("🖱️  %s button drag operation detected", button)
+
+	case hooks.EventDelayAdjusted:
+		if ev.Delay > ev.BaseDelay {
+			return fmt.Sprintf("🔧 ADAPTIVE STRICT: %s button delay increased to %.0fms due to detected switch bounce", button, delay)
+		}
+		return fmt.Sprintf("🔧 ADAPTIVE STRICT: %s button delay reset to user setting (%.0fms)", button, delay)
+
+	case hooks.EventHookInstalled:
+		return "🎯 Mouse hook installed successfully - protection active!"
+
+	case hooks.EventHookRemoved:
+		return "🛑 Mouse hook removed - protection stopped"
+
+	case hooks.EventDeviceGrabbed:
+		return fmt.Sprintf("🖱️  Grabbed %s", ev.Device)
+
+	case hooks.EventError:
+		if ev.Device != "" {
+			return fmt.Sprintf("❌ %s: %v", ev.Device, ev.Err)
+		}
+		return fmt.Sprintf("❌ %v", ev.Err)
+	}
+
+	return ""
+}
+
+// ms converts a duration to fractional milliseconds for display
+func ms(d time.Duration) float64 {
+	return float64(d.Nanoseconds()) / 1000000
+}
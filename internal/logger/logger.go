@@ -9,6 +9,8 @@ import (
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/widget"
+
+	"click-guardian/internal/hooks"
 )
 
 // Logger handles application logging with GUI display
@@ -75,6 +77,13 @@ func (l *Logger) Log(format string, args ...interface{}) {
 	}
 }
 
+// LogEvent formats a hook event and sends it to the log
+func (l *Logger) LogEvent(ev hooks.Event) {
+	if msg := FormatEvent(ev); msg != "" {
+		l.Log("%s", msg)
+	}
+}
+
 func (l *Logger) addLogEntry(msg string) {
 	timestamp := time.Now().Format("15:04:05")
 	logEntry := fmt.Sprintf("**[%s]** %s", timestamp, msg)
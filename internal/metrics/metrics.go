@@ -0,0 +1,128 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"click-guardian/internal/filter"
+)
+
+// Source provides the statistics to export
+type Source interface {
+	Stats() filter.Stats
+}
+
+// Server serves click statistics in the Prometheus text format
+type Server struct {
+	http     *http.Server
+	listener net.Listener
+}
+
+// Listen starts the exporter on a loopback address
+func Listen(address string, source Source) (*Server, error) {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid metrics address %q: %v", address, err)
+	}
+	if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+		return nil, fmt.Errorf("metrics address %q is not a loopback address", address)
+	}
+
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start metrics listener: %v", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		Write(w, source.Stats())
+	})
+
+	s := &Server{
+		http:     &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second},
+		listener: listener,
+	}
+	go s.http.Serve(listener)
+	return s, nil
+}
+
+// Address returns the address the exporter listens on
+func (s *Server) Address() string {
+	return s.listener.Addr().String()
+}
+
+// Close stops the exporter
+func (s *Server) Close() error {
+	return s.http.Close()
+}
+
+// Write renders stats in the Prometheus text exposition format
+func Write(w io.Writer, stats filter.Stats) error {
+	b := bufio.NewWriter(w)
+
+	header(b, "click_guardian_clicks_allowed_total", "counter", "Presses passed through per button.")
+	for _, button := range filter.Buttons() {
+		fmt.Fprintf(b, "click_guardian_clicks_allowed_total{button=%q} %d\n", label(button), stats.Allowed[button])
+	}
+
+	header(b, "click_guardian_events_blocked_total", "counter", "Button events blocked per button and reason.")
+	for _, button := range filter.Buttons() {
+		for _, reason := range filter.BlockReasons() {
+			fmt.Fprintf(b, "click_guardian_events_blocked_total{button=%q,reason=%q} %d\n",
+				label(button), reasonLabel(reason), stats.Blocked[button][reason])
+		}
+	}
+
+	header(b, "click_guardian_wheel_ticks_total", "counter", "Scroll wheel ticks by outcome.")
+	fmt.Fprintf(b, "click_guardian_wheel_ticks_total{result=\"allowed\"} %d\n", stats.WheelAllowed)
+	fmt.Fprintf(b, "click_guardian_wheel_ticks_total{result=\"blocked\"} %d\n", stats.WheelBlocked)
+
+	header(b, "click_guardian_effective_delay_seconds", "gauge", "Delay currently applied per button, including adaptive increases.")
+	for _, button := range filter.Buttons() {
+		if delay, ok := stats.Delays[button]; ok {
+			fmt.Fprintf(b, "click_guardian_effective_delay_seconds{button=%q} %g\n", label(button), delay.Seconds())
+		}
+	}
+
+	header(b, "click_guardian_press_interval_seconds", "histogram", "Time between presses of a button, blocked presses included.")
+	for _, button := range filter.Buttons() {
+		histogram(b, "click_guardian_press_interval_seconds", label(button), stats.Intervals[button])
+	}
+
+	header(b, "click_guardian_hold_duration_seconds", "histogram", "Time a button was held for allowed clicks.")
+	for _, button := range filter.Buttons() {
+		histogram(b, "click_guardian_hold_duration_seconds", label(button), stats.Holds[button])
+	}
+
+	return b.Flush()
+}
+
+func header(w io.Writer, name, kind, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+func histogram(w io.Writer, name, button string, h filter.Histogram) {
+	cumulative := 0
+	for i, bound := range filter.HistogramBuckets {
+		cumulative += h.Counts[i]
+		fmt.Fprintf(w, "%s_bucket{button=%q,le=\"%g\"} %d\n", name, button, bound.Seconds(), cumulative)
+	}
+	fmt.Fprintf(w, "%s_bucket{button=%q,le=\"+Inf\"} %d\n", name, button, h.Count)
+	fmt.Fprintf(w, "%s_sum{button=%q} %g\n", name, button, h.Sum.Seconds())
+	fmt.Fprintf(w, "%s_count{button=%q} %d\n", name, button, h.Count)
+}
+
+func label(button filter.Button) string {
+	return strings.ToLower(button.String())
+}
+
+// reasonLabel turns "rapid down" into "rapid_down"
+func reasonLabel(reason filter.Reason) string {
+	return strings.ReplaceAll(reason.String(), " ", "_")
+}
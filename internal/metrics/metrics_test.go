@@ -0,0 +1,78 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"click-guardian/internal/filter"
+)
+
+func TestWrite(t *testing.T) {
+	stats := filter.NewStats()
+	stats.Allowed[filter.ButtonLeft] = 12
+	stats.Blocked[filter.ButtonRight] = map[filter.Reason]int{filter.ReasonRapidDown: 3}
+	stats.WheelAllowed = 40
+	stats.WheelBlocked = 2
+	stats.Delays[filter.ButtonLeft] = 65 * time.Millisecond
+	var intervals filter.Histogram
+	for _, d := range []time.Duration{8 * time.Millisecond, 40 * time.Millisecond, 400 * time.Millisecond, 3 * time.Second} {
+		intervals.Observe(d)
+	}
+	stats.Intervals[filter.ButtonLeft] = intervals
+
+	var out strings.Builder
+	if err := Write(&out, stats); err != nil {
+		t.Fatal(err)
+	}
+	lines := make(map[string]bool)
+	for _, line := range strings.Split(out.String(), "\n") {
+		lines[line] = true
+	}
+
+	for _, want := range []string{
+		"# HELP click_guardian_clicks_allowed_total Presses passed through per button.",
+		"# TYPE click_guardian_clicks_allowed_total counter",
+		`click_guardian_clicks_allowed_total{button="left"} 12`,
+		`click_guardian_clicks_allowed_total{button="x2"} 0`,
+		"# TYPE click_guardian_events_blocked_total counter",
+		`click_guardian_events_blocked_total{button="right",reason="rapid_down"} 3`,
+		`click_guardian_events_blocked_total{button="right",reason="bounce_while_pressed"} 0`,
+		`click_guardian_wheel_ticks_total{result="allowed"} 40`,
+		`click_guardian_wheel_ticks_total{result="blocked"} 2`,
+		"# TYPE click_guardian_effective_delay_seconds gauge",
+		`click_guardian_effective_delay_seconds{button="left"} 0.065`,
+		"# TYPE click_guardian_press_interval_seconds histogram",
+		// Buckets are cumulative, the last one counts every observation
+		`click_guardian_press_interval_seconds_bucket{button="left",le="0.005"} 0`,
+		`click_guardian_press_interval_seconds_bucket{button="left",le="0.01"} 1`,
+		`click_guardian_press_interval_seconds_bucket{button="left",le="0.05"} 2`,
+		`click_guardian_press_interval_seconds_bucket{button="left",le="0.5"} 3`,
+		`click_guardian_press_interval_seconds_bucket{button="left",le="2"} 3`,
+		`click_guardian_press_interval_seconds_bucket{button="left",le="+Inf"} 4`,
+		`click_guardian_press_interval_seconds_sum{button="left"} 3.448`,
+		`click_guardian_press_interval_seconds_count{button="left"} 4`,
+		`click_guardian_hold_duration_seconds_count{button="middle"} 0`,
+	} {
+		if !lines[want] {
+			t.Errorf("missing line %q", want)
+		}
+	}
+
+	// Buttons without a delay have no gauge sample
+	if strings.Contains(out.String(), `click_guardian_effective_delay_seconds{button="right"}`) {
+		t.Error("delay reported for a button without one")
+	}
+	if got := strings.Count(out.String(), "# HELP "); got != 6 {
+		t.Errorf("%d metric families, want 6", got)
+	}
+}
+
+func TestListenRejectsRemoteAddresses(t *testing.T) {
+	for _, address := range []string{"0.0.0.0:9464", "192.168.1.10:9464", "example.com:9464", "9464"} {
+		if s, err := Listen(address, nil); err == nil {
+			s.Close()
+			t.Errorf("Listen(%q) succeeded, want an error", address)
+		}
+	}
+}
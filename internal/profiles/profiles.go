@@ -0,0 +1,182 @@
+package profiles
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"click-guardian/internal/config"
+	"click-guardian/internal/filter"
+	"click-guardian/internal/foreground"
+	"click-guardian/internal/hooks"
+)
+
+// PollInterval is how often the foreground application is checked
+const PollInterval = 500 * time.Millisecond
+
+// Switch reports that another profile became active
+type Switch struct {
+	Profile *config.Profile   // nil when the configured settings are back in effect
+	Window  foreground.Window // Application that caused the switch
+	Err     error             // Set instead when the foreground application cannot be determined
+}
+
+// Watcher switches the settings of a running hook to the profile of the
+// application in the foreground
+type Watcher struct {
+	hook       hooks.MouseHook
+	profiles   []config.Profile
+	notify     func(Switch)
+	foreground func() (foreground.Window, error) // Replaced by tests
+	failed     bool                              // Detection failed at the last poll
+	done       chan struct{}
+	stopped    chan struct{}
+
+	mu      sync.Mutex
+	configs []filter.Config // Filter settings of each profile
+	base    filter.Config   // Settings used when no profile matches
+	active  int             // Index of the active profile, -1 for the configured settings
+}
+
+// Start watches the foreground application until Stop is called. The
+// profiles are copied, so later changes to them need a restart; Update
+// takes over other changed settings. It returns nil when cfg has no
+// profiles.
+func Start(cfg *config.Config, hook hooks.MouseHook, notify func(Switch)) *Watcher {
+	if len(cfg.Profiles) == 0 {
+		return nil
+	}
+
+	w := newWatcher(cfg, hook, notify)
+	go w.run()
+	return w
+}
+
+// newWatcher returns a watcher that has not started polling
+func newWatcher(cfg *config.Config, hook hooks.MouseHook, notify func(Switch)) *Watcher {
+	w := &Watcher{
+		hook:       hook,
+		profiles:   append([]config.Profile(nil), cfg.Profiles...),
+		notify:     notify,
+		foreground: foreground.Active,
+		active:     -1,
+		done:       make(chan struct{}),
+		stopped:    make(chan struct{}),
+	}
+	w.load(cfg)
+	return w
+}
+
+// load derives the filter settings from cfg. Callers hold mu unless the
+// watcher is not running yet.
+func (w *Watcher) load(cfg *config.Config) {
+	w.base = cfg.FilterConfig()
+	w.configs = w.configs[:0]
+	for _, p := range w.profiles {
+		w.configs = append(w.configs, cfg.ProfileFilterConfig(p))
+	}
+}
+
+// Update takes over changed settings of cfg, such as the delay, and applies
+// them to the hook together with the active profile
+func (w *Watcher) Update(cfg *config.Config) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.load(cfg)
+	w.hook.SetConfig(w.current())
+}
+
+// current returns the settings in effect. Callers hold mu.
+func (w *Watcher) current() filter.Config {
+	if w.active < 0 {
+		return w.base
+	}
+	return w.configs[w.active]
+}
+
+// Stop ends watching and restores the configured settings
+func (w *Watcher) Stop() {
+	if w == nil {
+		return
+	}
+	close(w.done)
+	<-w.stopped
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.active >= 0 {
+		w.hook.SetConfig(w.base)
+	}
+}
+
+func (w *Watcher) run() {
+	defer close(w.stopped)
+	ticker := time.NewTicker(PollInterval)
+	defer ticker.Stop()
+
+	for {
+		w.poll()
+
+		select {
+		case <-ticker.C:
+		case <-w.done:
+			return
+		}
+	}
+}
+
+// poll switches to the profile of the application in the foreground now
+func (w *Watcher) poll() {
+	window, err := w.foreground()
+	if err != nil {
+		// Reported once until detection works again
+		if !w.failed {
+			w.notify(Switch{Err: err})
+		}
+		w.failed = true
+		return
+	}
+	w.failed = false
+	w.switchTo(w.match(window), window)
+}
+
+// match returns the index of the first profile matching window, or -1
+func (w *Watcher) match(window foreground.Window) int {
+	for i, p := range w.profiles {
+		if window.Matches(p.Match) {
+			return i
+		}
+	}
+	return -1
+}
+
+// switchTo switches the hook to a profile, or to the configured settings
+// for -1, and reports it unless it is already active
+func (w *Watcher) switchTo(index int, window foreground.Window) {
+	w.mu.Lock()
+	if index == w.active {
+		w.mu.Unlock()
+		return
+	}
+	w.active = index
+	w.hook.SetConfig(w.current())
+	w.mu.Unlock()
+
+	if index < 0 {
+		w.notify(Switch{Window: window})
+		return
+	}
+	w.notify(Switch{Profile: &w.profiles[index], Window: window})
+}
+
+// Describe returns a log line for a switch
+func (s Switch) Describe() string {
+	switch {
+	case s.Err != nil:
+		return fmt.Sprintf("⚠️ Application profiles paused: %v", s.Err)
+	case s.Profile == nil:
+		return fmt.Sprintf("🗂️ %s in the foreground, using the default settings", s.Window)
+	default:
+		return fmt.Sprintf("🗂️ Profile %q active for %s (%s)", s.Profile.Name, s.Window, s.Profile.Summary())
+	}
+}
@@ -0,0 +1,153 @@
+package profiles
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"click-guardian/internal/config"
+	"click-guardian/internal/filter"
+	"click-guardian/internal/foreground"
+	"click-guardian/internal/hooks"
+)
+
+// fakeHook records the settings it is switched to
+type fakeHook struct {
+	mu      sync.Mutex
+	configs []filter.Config
+}
+
+func (h *fakeHook) Start(filter.Config, chan<- hooks.Event) error { return nil }
+func (h *fakeHook) Stop() error                                   { return nil }
+func (h *fakeHook) GetBlockedCount() int                          { return 0 }
+func (h *fakeHook) ResetBlockedCount()                            {}
+func (h *fakeHook) Stats() filter.Stats                           { return filter.NewStats() }
+func (h *fakeHook) SetObserver(filter.Observer)                   {}
+func (h *fakeHook) Devices() ([]hooks.Device, error)              { return nil, nil }
+func (h *fakeHook) IsSupported() bool                             { return true }
+
+func (h *fakeHook) SetConfig(cfg filter.Config) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.configs = append(h.configs, cfg)
+}
+
+// delays returns the delay of every SetConfig call so far
+func (h *fakeHook) delays() []time.Duration {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var delays []time.Duration
+	for _, cfg := range h.configs {
+		delays = append(delays, cfg.Delay)
+	}
+	return delays
+}
+
+func testConfig() *config.Config {
+	c := config.DefaultConfig()
+	c.DelayMs = 50
+	c.Profiles = []config.Profile{
+		{Name: "Game", Match: "game.exe", Enabled: true, DelayMs: 120},
+		{Name: "Editor", Match: "editor", Enabled: false},
+	}
+	return c
+}
+
+func TestWatcherSwitches(t *testing.T) {
+	hook := &fakeHook{}
+	var switches []Switch
+	w := newWatcher(testConfig(), hook, func(s Switch) {
+		switches = append(switches, s)
+	})
+
+	var window foreground.Window
+	var detectErr error
+	w.foreground = func() (foreground.Window, error) {
+		return window, detectErr
+	}
+
+	steps := []struct {
+		window  string
+		err     error
+		profile string // Name of the profile reported, "-" for the configured settings, "" for none
+		delay   time.Duration
+	}{
+		// Nothing to switch from at the start
+		{window: "terminal"},
+		{window: "game.exe", profile: "Game", delay: 120 * time.Millisecond},
+		{window: "GAME.EXE"},
+		{window: "editor.exe", profile: "Editor", delay: 50 * time.Millisecond},
+		{window: "terminal", profile: "-", delay: 50 * time.Millisecond},
+		// Failures are reported once and keep the settings
+		{err: errors.New("no display"), profile: "!"},
+		{err: errors.New("no display")},
+		{window: "game.exe", profile: "Game", delay: 120 * time.Millisecond},
+	}
+
+	for i, s := range steps {
+		window, detectErr = foreground.Window{Executable: s.window}, s.err
+		before, calls := len(switches), len(hook.delays())
+		w.poll()
+
+		if s.profile == "" {
+			if len(switches) != before || len(hook.delays()) != calls {
+				t.Errorf("step %d (%s): switched to %+v", i, s.window, switches[before:])
+			}
+			continue
+		}
+		if len(switches) != before+1 {
+			t.Fatalf("step %d (%s): %d switches reported, want one", i, s.window, len(switches)-before)
+		}
+		got := switches[before]
+		switch {
+		case s.profile == "!":
+			if got.Err == nil {
+				t.Errorf("step %d: reported %+v, want the detection error", i, got)
+			}
+			continue
+		case s.profile == "-":
+			if got.Profile != nil {
+				t.Errorf("step %d (%s): switched to profile %q, want the configured settings", i, s.window, got.Profile.Name)
+			}
+		case got.Profile == nil || got.Profile.Name != s.profile:
+			t.Errorf("step %d (%s): switched to %+v, want profile %q", i, s.window, got.Profile, s.profile)
+		}
+		if delays := hook.delays(); len(delays) != calls+1 || delays[calls] != s.delay {
+			t.Errorf("step %d (%s): hook delays %v, want %v added", i, s.window, delays[calls:], s.delay)
+		}
+	}
+
+	// The disabled profile filters nothing
+	if w.configs[1].Enabled(filter.ButtonLeft) {
+		t.Error("disabled profile filters the left button")
+	}
+}
+
+func TestWatcherUpdateAndStop(t *testing.T) {
+	hook := &fakeHook{}
+	cfg := testConfig()
+	w := newWatcher(cfg, hook, func(Switch) {})
+	w.foreground = func() (foreground.Window, error) {
+		return foreground.Window{Executable: "game.exe"}, nil
+	}
+	go w.run()
+
+	// The first poll happens right away
+	deadline := time.Now().Add(5 * time.Second)
+	for len(hook.delays()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	// A new delay leaves the active profile in effect and applies once the
+	// configured settings are restored
+	cfg.DelayMs = 70
+	w.Update(cfg)
+	w.Stop()
+
+	want := []time.Duration{120 * time.Millisecond, 120 * time.Millisecond, 70 * time.Millisecond}
+	if got := hook.delays(); !reflect.DeepEqual(got, want) {
+		t.Errorf("hook delays %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,186 @@
+package trace
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"click-guardian/internal/filter"
+)
+
+// Recorder writes every observed event to a trace file in JSON Lines format.
+// Observe never blocks; entries are written by a background goroutine.
+type Recorder struct {
+	path    string
+	file    *os.File
+	writer  *bufio.Writer
+	entries chan Entry
+	done    chan struct{}
+	clock   func() time.Time // Monotonic clock, replaced by tests
+
+	mu         sync.Mutex
+	start      time.Time     // Monotonic time of the first entry
+	last       time.Time     // Time of the last event
+	lastOffset time.Duration // Offset of the last event
+	closed     bool
+	dropped    int
+}
+
+// maxClockSkew is how far the event times may drift from the monotonic
+// clock before the recorder stops trusting them, e.g. after the wall clock
+// was set
+const maxClockSkew = 100 * time.Millisecond
+
+// NewRecorder creates the trace file and writes its header
+func NewRecorder(path string, cfg filter.Config) (*Recorder, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace file: %v", err)
+	}
+
+	r := &Recorder{
+		path:    path,
+		file:    f,
+		writer:  bufio.NewWriter(f),
+		entries: make(chan Entry, 4096),
+		done:    make(chan struct{}),
+		clock:   time.Now,
+	}
+
+	header := Header{Version: Version, Started: time.Now(), Settings: SettingsFromConfig(cfg)}
+	if err := r.writeLine(header); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to write trace header: %v", err)
+	}
+
+	go r.run()
+	return r, nil
+}
+
+// Path returns the file the trace is written to
+func (r *Recorder) Path() string {
+	return r.path
+}
+
+// Observe queues an event and its decision; it matches filter.Observer
+func (r *Recorder) Observe(ev filter.Event, d filter.Decision) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return
+	}
+
+	e := Entry{
+		Offset:   int64(r.offset(ev.Time)),
+		Kind:     ev.Kind.String(),
+		Allowed:  d.Allow,
+		Bypassed: d.Bypassed,
+		Device:   ev.DeviceID,
+		Source:   ev.DeviceKey,
+	}
+	switch ev.Kind {
+	case filter.KindDown, filter.KindUp:
+		e.Button = strings.ToLower(ev.Button.String())
+	case filter.KindWheel:
+		e.Delta = ev.Delta
+	}
+	if !d.Allow {
+		e.Reason = d.Reason.String()
+	}
+	r.queue(e)
+}
+
+// Stopped marks that protection stopped; the events that follow are not
+// recorded until Started
+func (r *Recorder) Stopped() {
+	r.mark(KindStopped)
+}
+
+// Started marks that protection started again with fresh filter state
+func (r *Recorder) Started() {
+	r.mark(KindStarted)
+}
+
+func (r *Recorder) mark(kind string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return
+	}
+	// The next event is placed by the monotonic clock alone
+	r.last = time.Time{}
+	r.queue(Entry{Offset: int64(r.offset(time.Time{})), Kind: kind, Allowed: true})
+}
+
+// offset returns the offset of an event stamped at t. Offsets follow the
+// monotonic clock; the event times, which are wall clock, only refine them
+// while they agree with it. Callers hold mu.
+func (r *Recorder) offset(t time.Time) time.Duration {
+	now := r.clock()
+	if r.start.IsZero() {
+		r.start = now
+	}
+	offset := now.Sub(r.start)
+	if !r.last.IsZero() && !t.IsZero() {
+		if stamped := r.lastOffset + t.Sub(r.last); (stamped - offset).Abs() < maxClockSkew {
+			offset = stamped
+		}
+	}
+	r.last, r.lastOffset = t, offset
+	return offset
+}
+
+// queue hands an entry to the writer. Callers hold mu.
+func (r *Recorder) queue(e Entry) {
+	select {
+	case r.entries <- e:
+	default:
+		// Writer is behind, drop the entry rather than stall the hook
+		r.dropped++
+	}
+}
+
+// Close flushes the trace and returns the number of dropped entries
+func (r *Recorder) Close() (int, error) {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return r.dropped, nil
+	}
+	r.closed = true
+	close(r.entries)
+	r.mu.Unlock()
+
+	<-r.done
+	err := r.writer.Flush()
+	if cerr := r.file.Close(); err == nil {
+		err = cerr
+	}
+	return r.dropped, err
+}
+
+func (r *Recorder) run() {
+	defer close(r.done)
+	for e := range r.entries {
+		if err := r.writeLine(e); err != nil {
+			// Keep draining so Observe never blocks
+			continue
+		}
+	}
+}
+
+func (r *Recorder) writeLine(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	_, err = r.writer.Write(data)
+	return err
+}
@@ -0,0 +1,77 @@
+package trace
+
+import (
+	"time"
+
+	"click-guardian/internal/filter"
+)
+
+// Result compares the recorded decision of an entry with a replayed one
+type Result struct {
+	Entry    Entry
+	Event    filter.Event
+	Decision filter.Decision // Decision of the replayed filter
+}
+
+// Changed reports whether the replayed filter decided differently
+func (r Result) Changed() bool {
+	return r.Entry.Allowed != r.Decision.Allow
+}
+
+// Summary counts blocked events of a replay
+type Summary struct {
+	Events          int
+	RecordedBlocked int
+	ReplayBlocked   int
+	NewlyBlocked    int // Allowed when recorded, blocked by the replay
+	NewlyAllowed    int // Blocked when recorded, allowed by the replay
+}
+
+// Replay feeds the trace through fresh engines configured with cfg, one per
+// recorded device. The engines start over wherever protection was started
+// again, like those of the hook did.
+func (t *Trace) Replay(cfg filter.Config) ([]Result, Summary, error) {
+	engines := filter.NewEngineSet(cfg)
+	start := time.Unix(0, 0)
+
+	results := make([]Result, 0, len(t.Entries))
+	var summary Summary
+	for _, e := range t.Entries {
+		switch e.Kind {
+		case KindStopped:
+			continue
+		case KindStarted:
+			engines = filter.NewEngineSet(cfg)
+			continue
+		}
+
+		ev, err := e.Event(start)
+		if err != nil {
+			return nil, summary, err
+		}
+		// Bypassed events never reached the filter
+		d := filter.Decision{Allow: true, Bypassed: true}
+		if !e.Bypassed {
+			d = engines.Process(ev)
+		}
+		results = append(results, Result{Entry: e, Event: ev, Decision: d})
+
+		if ev.Kind == filter.KindMove {
+			continue
+		}
+		summary.Events++
+		if !e.Allowed {
+			summary.RecordedBlocked++
+		}
+		if !d.Allow {
+			summary.ReplayBlocked++
+		}
+		switch {
+		case e.Allowed && !d.Allow:
+			summary.NewlyBlocked++
+		case !e.Allowed && d.Allow:
+			summary.NewlyAllowed++
+		}
+	}
+	return results, summary, nil
+}
@@ -0,0 +1,213 @@
+package trace
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"click-guardian/internal/filter"
+)
+
+// Version is the trace file format version
+const Version = 1
+
+// Header is the first line of a trace file
+type Header struct {
+	Version  int       `json:"version"`
+	Started  time.Time `json:"started"`
+	Settings Settings  `json:"settings"`
+}
+
+// Settings is the JSON form of a filter.Config
+type Settings struct {
+	DelayMs       int            `json:"delay_ms"`
+	ButtonDelayMs map[string]int `json:"button_delay_ms,omitempty"`
+	Disabled      []string       `json:"disabled,omitempty"`
+	WheelFilter   bool           `json:"wheel_filter"`
+	WheelWindowMs int            `json:"wheel_window_ms"`
+
+	// Missing from traces recorded before strategies existed, which
+	// replay with the strict strategy and the default window
+	Strategy            string `json:"strategy,omitempty"`
+	DoubleClickWindowMs int    `json:"double_click_window_ms,omitempty"`
+
+	// Per-device overrides keyed by vendor:product ID
+	DeviceDelayMs   map[string]int `json:"device_delay_ms,omitempty"`
+	DisabledDevices []string       `json:"disabled_devices,omitempty"`
+}
+
+// Kinds of the entries that mark where protection stopped and started
+// again. Events in between passed unfiltered and are missing from the trace.
+const (
+	KindStopped = "stopped"
+	KindStarted = "started"
+)
+
+// Entry is one recorded event and the decision the filter took
+type Entry struct {
+	Offset  int64  `json:"t"` // Monotonic nanoseconds since the first entry
+	Kind    string `json:"k"`
+	Button  string `json:"b,omitempty"`
+	Delta   int    `json:"d,omitempty"`
+	Allowed bool   `json:"allow"`
+	Reason  string `json:"reason,omitempty"`
+
+	// Bypassed is set on events that skipped the filter while the bypass
+	// modifier was held
+	Bypassed bool `json:"bypass,omitempty"`
+
+	// Device is the vendor:product ID of the device, Source tells identical
+	// devices apart. Both are empty when the device was not known.
+	Device string `json:"dev,omitempty"`
+	Source string `json:"src,omitempty"`
+}
+
+// Trace is a loaded trace file
+type Trace struct {
+	Header  Header
+	Entries []Entry
+}
+
+// SettingsFromConfig converts a filter configuration for the header
+func SettingsFromConfig(cfg filter.Config) Settings {
+	s := Settings{
+		DelayMs:       int(cfg.Delay / time.Millisecond),
+		ButtonDelayMs: make(map[string]int),
+		WheelFilter:   cfg.Wheel.Enabled,
+		WheelWindowMs: int(cfg.Wheel.Window / time.Millisecond),
+
+		Strategy:            cfg.Strategy.String(),
+		DoubleClickWindowMs: int(cfg.DoubleClickWindow / time.Millisecond),
+	}
+	for _, b := range filter.Buttons() {
+		name := strings.ToLower(b.String())
+		if !cfg.Enabled(b) {
+			s.Disabled = append(s.Disabled, name)
+		}
+		if bc := cfg.Buttons[b]; bc.Delay > 0 {
+			s.ButtonDelayMs[name] = int(bc.Delay / time.Millisecond)
+		}
+	}
+	for id, dc := range cfg.Devices {
+		if !dc.Enabled {
+			s.DisabledDevices = append(s.DisabledDevices, id)
+		}
+		if dc.Delay > 0 {
+			if s.DeviceDelayMs == nil {
+				s.DeviceDelayMs = make(map[string]int)
+			}
+			s.DeviceDelayMs[id] = int(dc.Delay / time.Millisecond)
+		}
+	}
+	sort.Strings(s.DisabledDevices)
+	return s
+}
+
+// Config converts the settings back into a filter configuration
+func (s Settings) Config() filter.Config {
+	cfg := filter.NewConfig(time.Duration(s.DelayMs) * time.Millisecond)
+	for name, ms := range s.ButtonDelayMs {
+		if b, ok := filter.ParseButton(name); ok {
+			bc := cfg.Buttons[b]
+			bc.Delay = time.Duration(ms) * time.Millisecond
+			cfg.Buttons[b] = bc
+		}
+	}
+	for _, name := range s.Disabled {
+		if b, ok := filter.ParseButton(name); ok {
+			bc := cfg.Buttons[b]
+			bc.Enabled = false
+			cfg.Buttons[b] = bc
+		}
+	}
+	cfg.Wheel = filter.WheelConfig{
+		Enabled: s.WheelFilter,
+		Window:  time.Duration(s.WheelWindowMs) * time.Millisecond,
+	}
+	if strategy, ok := filter.ParseStrategy(s.Strategy); ok {
+		cfg.Strategy = strategy
+	}
+	if s.DoubleClickWindowMs > 0 {
+		cfg.DoubleClickWindow = time.Duration(s.DoubleClickWindowMs) * time.Millisecond
+	}
+	if len(s.DeviceDelayMs) > 0 || len(s.DisabledDevices) > 0 {
+		cfg.Devices = make(map[string]filter.DeviceConfig)
+		for id, ms := range s.DeviceDelayMs {
+			cfg.Devices[id] = filter.DeviceConfig{Enabled: true, Delay: time.Duration(ms) * time.Millisecond}
+		}
+		for _, id := range s.DisabledDevices {
+			dc := cfg.Devices[id]
+			dc.Enabled = false
+			cfg.Devices[id] = dc
+		}
+	}
+	return cfg
+}
+
+// Event converts the entry back into a filter event relative to start
+func (e Entry) Event(start time.Time) (filter.Event, error) {
+	ev := filter.Event{
+		Time:      start.Add(time.Duration(e.Offset)),
+		Delta:     e.Delta,
+		DeviceKey: e.Source,
+		DeviceID:  e.Device,
+	}
+
+	found := false
+	for _, kind := range []filter.Kind{filter.KindDown, filter.KindUp, filter.KindMove, filter.KindWheel} {
+		if kind.String() == e.Kind {
+			ev.Kind, found = kind, true
+			break
+		}
+	}
+	if !found {
+		return ev, fmt.Errorf("unknown event kind %q", e.Kind)
+	}
+
+	if ev.Kind == filter.KindDown || ev.Kind == filter.KindUp {
+		b, ok := filter.ParseButton(e.Button)
+		if !ok {
+			return ev, fmt.Errorf("unknown button %q", e.Button)
+		}
+		ev.Button = b
+	}
+	return ev, nil
+}
+
+// Load reads a trace file
+func Load(path string) (*Trace, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open trace: %v", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	if !scanner.Scan() {
+		return nil, fmt.Errorf("trace %s is empty", path)
+	}
+
+	t := &Trace{}
+	if err := json.Unmarshal(scanner.Bytes(), &t.Header); err != nil {
+		return nil, fmt.Errorf("invalid trace header: %v", err)
+	}
+	if t.Header.Version != Version {
+		return nil, fmt.Errorf("unsupported trace version %d", t.Header.Version)
+	}
+
+	for line := 2; scanner.Scan(); line++ {
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("invalid trace entry on line %d: %v", line, err)
+		}
+		t.Entries = append(t.Entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read trace: %v", err)
+	}
+	return t, nil
+}
@@ -0,0 +1,157 @@
+package trace
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"click-guardian/internal/filter"
+)
+
+func TestSettingsRoundTrip(t *testing.T) {
+	cfg := filter.NewConfig(60 * time.Millisecond)
+	cfg.Buttons[filter.ButtonRight] = filter.ButtonConfig{Enabled: true, Delay: 90 * time.Millisecond}
+	cfg.Buttons[filter.ButtonX2] = filter.ButtonConfig{Enabled: false}
+	cfg.Wheel = filter.WheelConfig{Enabled: true, Window: 150 * time.Millisecond}
+	cfg.Strategy = filter.StrategyDoubleClick
+	cfg.DoubleClickWindow = 400 * time.Millisecond
+	cfg.Devices = map[string]filter.DeviceConfig{
+		"046d:c077": {Enabled: true, Delay: 80 * time.Millisecond},
+		"1532:0084": {Enabled: false},
+		"045e:0040": {Enabled: false, Delay: 30 * time.Millisecond},
+	}
+
+	if got := SettingsFromConfig(cfg).Config(); !reflect.DeepEqual(got, cfg) {
+		t.Errorf("round trip changed the configuration:\n got %+v\nwant %+v", got, cfg)
+	}
+	if got := (Settings{DelayMs: 50}).Config(); got.Strategy != filter.StrategyStrict || got.DoubleClickWindow != filter.DefaultDoubleClickWindow {
+		t.Errorf("settings without a strategy replay with %s and %v, want strict and the default window",
+			got.Strategy, got.DoubleClickWindow)
+	}
+}
+
+// fakeClock is a monotonic clock that only moves when told to
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func TestReplayReproducesVerdicts(t *testing.T) {
+	cfg := filter.NewConfig(50 * time.Millisecond)
+	cfg.Wheel.Enabled = true
+
+	path := filepath.Join(t.TempDir(), "trace.jsonl")
+	r, err := NewRecorder(path, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	base := time.Now()
+	clock := &fakeClock{now: base}
+	r.clock = clock.Now
+
+	// Live filtering, recorded as it happens
+	engines := filter.NewEngineSet(cfg)
+	start := time.Date(2024, 3, 30, 12, 0, 0, 0, time.UTC)
+	at := func(ms int) time.Time {
+		clock.now = base.Add(time.Duration(ms) * time.Millisecond)
+		return start.Add(time.Duration(ms) * time.Millisecond)
+	}
+	live := func(ms int, kind filter.Kind, button filter.Button, delta int, key string) {
+		ev := filter.Event{
+			Kind:      kind,
+			Button:    button,
+			Delta:     delta,
+			Time:      at(ms),
+			DeviceKey: key,
+			DeviceID:  "046d:c077",
+		}
+		r.Observe(ev, engines.Process(ev))
+	}
+	const mouse, other = "/dev/input/event3", "/dev/input/event7"
+	left := filter.ButtonLeft
+
+	live(0, filter.KindDown, left, 0, mouse)
+	live(80, filter.KindUp, left, 0, mouse)
+	live(95, filter.KindDown, left, 0, mouse) // Bounce
+	live(100, filter.KindDown, left, 0, other)
+	live(105, filter.KindUp, left, 0, mouse)
+	live(180, filter.KindUp, left, 0, other)
+	for _, ms := range []int{300, 330, 360} {
+		live(ms, filter.KindWheel, 0, 1, mouse)
+	}
+	live(390, filter.KindWheel, 0, -1, mouse) // Reversal
+
+	// A restart clears the state the next press would otherwise bounce on
+	live(400, filter.KindDown, left, 0, mouse)
+	r.Stopped()
+	r.Started()
+	engines = filter.NewEngineSet(cfg)
+	live(420, filter.KindDown, left, 0, mouse)
+
+	// The bypass modifier lets a bounce through
+	bypassed := filter.Event{Kind: filter.KindDown, Button: left, Time: at(430), DeviceKey: mouse}
+	r.Observe(bypassed, filter.Decision{Allow: true, Bypassed: true})
+
+	if dropped, err := r.Close(); err != nil || dropped != 0 {
+		t.Fatalf("closing the recorder: %d dropped, %v", dropped, err)
+	}
+
+	tr, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	results, summary, err := tr.Replay(tr.Header.Settings.Config())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 13 {
+		t.Errorf("replayed %d events, want 13 without the markers", len(results))
+	}
+	for _, res := range results {
+		if res.Changed() {
+			t.Errorf("%s at %v: recorded allowed=%v, replayed %+v",
+				res.Entry.Kind, time.Duration(res.Entry.Offset), res.Entry.Allowed, res.Decision)
+		}
+	}
+	if summary.RecordedBlocked != 3 || summary.ReplayBlocked != 3 {
+		t.Errorf("blocked %d recorded and %d replayed, want the bounce, its release and the reversal",
+			summary.RecordedBlocked, summary.ReplayBlocked)
+	}
+}
+
+func TestRecorderOffsetsIgnoreClockJumps(t *testing.T) {
+	r, err := NewRecorder(filepath.Join(t.TempDir(), "trace.jsonl"), filter.NewConfig(50*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	clock := &fakeClock{now: time.Now()}
+	r.clock = clock.Now
+
+	wall := time.Date(2024, 3, 30, 12, 0, 0, 0, time.UTC)
+	steps := []struct {
+		advance time.Duration // Of the monotonic clock
+		wall    time.Duration // Of the event time
+		want    time.Duration
+	}{
+		{0, 0, 0},
+		// Event times refine the offset while they agree with the clock
+		{25 * time.Millisecond, 20 * time.Millisecond, 20 * time.Millisecond},
+		// The wall clock was set back an hour
+		{30 * time.Millisecond, -time.Hour, 55 * time.Millisecond},
+		{10 * time.Millisecond, -time.Hour + 12*time.Millisecond, 67 * time.Millisecond},
+	}
+	for i, s := range steps {
+		clock.now = clock.now.Add(s.advance)
+		r.mu.Lock()
+		got := r.offset(wall.Add(s.wall))
+		r.mu.Unlock()
+		if got != s.want {
+			t.Errorf("step %d: offset %v, want %v", i, got, s.want)
+		}
+	}
+}
@@ -18,8 +18,8 @@ func GetInfo() Info {
 		Architecture: runtime.GOARCH,
 	}
 
-	// Currently only Windows is fully supported
-	info.IsSupported = info.OS == "windows"
+	// Windows uses a low-level hook, Linux uses evdev/uinput
+	info.IsSupported = info.OS == "windows" || info.OS == "linux"
 
 	return info
 }
@@ -41,10 +41,10 @@ func IsMacOS() bool {
 
 // SupportedPlatforms returns a list of platforms that support mouse hooking
 func SupportedPlatforms() []string {
-	return []string{"windows"}
+	return []string{"windows", "linux"}
 }
 
 // PlannedPlatforms returns a list of platforms planned for future support
 func PlannedPlatforms() []string {
-	return []string{"linux", "darwin"}
+	return []string{"darwin"}
 }
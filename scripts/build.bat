@@ -53,10 +53,22 @@ if %ERRORLEVEL% EQU 0 (
     exit /b 1
 )
 
+REM Build the control client
+echo Building control client...
+go build -ldflags "-s -w" -o dist\click-guardianctl.exe .\cmd\click-guardianctl
+
+if %ERRORLEVEL% EQU 0 (
+    echo ✅ Control client build successful! Created dist\click-guardianctl.exe
+) else (
+    echo ❌ Control client build failed with error code %ERRORLEVEL%!
+    exit /b 1
+)
+
 echo.
 echo Build complete! Executables are in the dist folder.
 echo - dist\click-guardian-gui.exe (recommended for normal use)
 echo - dist\click-guardian.exe (for debugging/console output)
+echo - dist\click-guardianctl.exe (controls a running instance)
 
 REM Don't pause in CI environment
 if not defined GITHUB_ACTIONS pause
\ No newline at end of file
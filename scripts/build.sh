@@ -37,9 +37,12 @@ for target in "${!targets[@]}"; do
         GOOS=$GOOS GOARCH=$GOARCH go build -ldflags "-s -w -H=windowsgui" -o "${output%.*}-gui.exe" ./cmd/click-guardian
         # Console version for Windows
         GOOS=$GOOS GOARCH=$GOARCH go build -ldflags "-s -w" -o "$output" ./cmd/click-guardian
+        # Control client
+        GOOS=$GOOS GOARCH=$GOARCH go build -ldflags "-s -w" -o "${output/click-guardian/click-guardianctl}" ./cmd/click-guardianctl
     else
         # Regular build for other platforms
         GOOS=$GOOS GOARCH=$GOARCH go build -ldflags "-s -w" -o "$output" ./cmd/click-guardian
+        GOOS=$GOOS GOARCH=$GOARCH go build -ldflags "-s -w" -o "${output/click-guardian/click-guardianctl}" ./cmd/click-guardianctl
     fi
     
     if [ $? -eq 0 ]; then
@@ -54,5 +57,5 @@ echo "Cross-platform build complete! Executables are in the dist folder:"
 ls -la dist/
 
 echo ""
-echo "Note: Only Windows builds will have full mouse hooking functionality."
-echo "Other platforms will show an unsupported message."
+echo "Note: Windows and Linux builds have full mouse hooking functionality."
+echo "macOS builds will show an unsupported message."